@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/adharshmk96/stktemplate/server"
+	"github.com/spf13/cobra"
+)
+
+// routesCmd represents the routes command
+var routesCmd = &cobra.Command{
+	Use:   "routes",
+	Short: "List the registered routes",
+	Run: func(cmd *cobra.Command, args []string) {
+		httpServer := server.NewHttpServer("0.0.0.0:" + startingPort)
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "METHOD\tPATH\tHANDLER\tMIDDLEWARES")
+		for _, route := range httpServer.Routes() {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", route.Method, route.Path, route.Handler, strings.Join(route.Middlewares, ", "))
+		}
+		writer.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(routesCmd)
+}
@@ -1,13 +1,18 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/adharshmk96/stktemplate/internals/ping/domain"
+	"github.com/adharshmk96/stktemplate/internals/ping/serr"
 
 	"github.com/adharshmk96/stk/gsk"
 )
 
+// errPingFailed is the response for serr.ErrPingFailed, the domain errors are mapped to responses in the handlers
+var errPingFailed = gsk.NewHTTPError(http.StatusInternalServerError, "ping_failed", "ping failed")
+
 type pingHandler struct {
 	service domain.PingService
 }
@@ -24,17 +29,18 @@ Response:
 - 200: OK
 - 500: Internal Server Error
 */
-func (h *pingHandler) PingHandler(gc *gsk.Context) {
+func (h *pingHandler) PingHandler(gc *gsk.Context) error {
 
 	message, err := h.service.PingService()
+	if errors.Is(err, serr.ErrPingFailed) {
+		return errPingFailed.Wrap(err)
+	}
 	if err != nil {
-		gc.Status(http.StatusInternalServerError).JSONResponse(gsk.Map{
-			"error": err.Error(),
-		})
-		return
+		return err
 	}
 
 	gc.Status(http.StatusOK).JSONResponse(gsk.Map{
 		"message": message,
 	})
+	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/adharshmk96/stktemplate/internals/ping/api/handler"
+	"github.com/adharshmk96/stktemplate/internals/ping/serr"
 
 	"github.com/adharshmk96/stk/gsk"
 	"github.com/adharshmk96/stktemplate/mocks"
@@ -25,7 +26,7 @@ func TestPingHandler(t *testing.T) {
 
 		pingHandler := handler.NewPingHandler(service)
 
-		s.Get("/ping", pingHandler.PingHandler)
+		s.Get("/ping", gsk.E(pingHandler.PingHandler))
 
 		// Act
 		w, _ := s.Test("GET", "/ping", nil)
@@ -33,4 +34,23 @@ func TestPingHandler(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
+
+	t.Run("Ping Handler returns 500 when ping fails", func(t *testing.T) {
+
+		// Arrange
+		s := gsk.New()
+		service := mocks.NewPingService(t)
+		service.On("PingService").Return("", serr.ErrPingFailed)
+
+		pingHandler := handler.NewPingHandler(service)
+
+		s.Get("/ping", gsk.E(pingHandler.PingHandler))
+
+		// Act
+		w, _ := s.Test("GET", "/ping", nil)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, "{\"error\": \"ping_failed\", \"message\": \"ping failed\"}", w.Body.String())
+	})
 }
@@ -4,5 +4,5 @@ import "github.com/adharshmk96/stk/gsk"
 
 // Handler
 type PingHandlers interface {
-	PingHandler(gc *gsk.Context)
+	PingHandler(gc *gsk.Context) error
 }
@@ -25,7 +25,10 @@ func SetupApiRoutes(rg *gsk.RouteGroup) {
 
 	pingRoutes := rg.RouteGroup("/ping")
 
-	pingRoutes.Get("/", pingHandler.PingHandler)
+	pingRoutes.Get("/", gsk.E(pingHandler.PingHandler)).HandlerName("handler.PingHandler").Document(gsk.RouteDoc{
+		Summary: "Ping the database",
+		Tags:    []string{"ping"},
+	})
 }
 
 func SetupWebRoutes(rg *gsk.RouteGroup) {
@@ -7,7 +7,7 @@ import (
 func HomeHandler(gc *gsk.Context) {
 
 	gc.TemplateResponse(&gsk.Tpl{
-		TemplatePath: "public/templates/index.html",
+		TemplatePath: "index.html",
 		Variables: gsk.Map{
 			"Title":   "Ping",
 			"Content": "Welcome to the ping page!",
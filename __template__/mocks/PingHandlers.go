@@ -13,8 +13,17 @@ type PingHandlers struct {
 }
 
 // PingHandler provides a mock function with given fields: gc
-func (_m *PingHandlers) PingHandler(gc *gsk.Context) {
-	_m.Called(gc)
+func (_m *PingHandlers) PingHandler(gc *gsk.Context) error {
+	ret := _m.Called(gc)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gsk.Context) error); ok {
+		r0 = rf(gc)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 type mockConstructorTestingTNewPingHandlers interface {
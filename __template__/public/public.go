@@ -0,0 +1,21 @@
+package public
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// Files embeds the assets and templates into the binary
+// so that the binary can be shipped without the public directory
+//
+//go:embed assets templates
+var Files embed.FS
+
+// AssetsFS returns the static assets with the assets directory as the root
+func AssetsFS() fs.FS {
+	assets, err := fs.Sub(Files, "assets")
+	if err != nil {
+		panic(err)
+	}
+	return assets
+}
@@ -2,6 +2,7 @@ package routing
 
 import (
 	"github.com/adharshmk96/stk/gsk"
+	svrmw "github.com/adharshmk96/stktemplate/server/middleware"
 )
 
 var webRouteGroups = []func(*gsk.RouteGroup){}
@@ -17,6 +18,8 @@ func RegisterWebRoutes(routeGroup func(*gsk.RouteGroup)) {
 
 func SetupApiRoutes(server *gsk.Server) {
 	apiRoutes := server.RouteGroup("/api")
+	// only the api is rate limited, health checks and metrics are scraped often
+	apiRoutes.Use(svrmw.RateLimiter())
 
 	for _, routeGroup := range apiRouteGroups {
 		routeGroup(apiRoutes)
@@ -6,25 +6,40 @@ import (
 	"syscall"
 
 	"github.com/adharshmk96/stk/gsk"
+	"github.com/adharshmk96/stk/pkg/health"
 	"github.com/adharshmk96/stk/pkg/middleware"
+	"github.com/adharshmk96/stktemplate/public"
 	"github.com/adharshmk96/stktemplate/server/infra"
-	svrmw "github.com/adharshmk96/stktemplate/server/middleware"
+	"github.com/adharshmk96/stktemplate/server/infra/db"
 	"github.com/adharshmk96/stktemplate/server/routing"
 )
 
-func StartHttpServer(port string) (*gsk.Server, chan bool) {
+// NewHttpServer creates the server with the middlewares and routes
+func NewHttpServer(port string) *gsk.Server {
 
 	logger := infra.GetLogger()
 
+	templateEngine, err := gsk.NewTemplateEngine(gsk.TemplateEngineConfig{
+		FS:  public.Files,
+		Dir: "templates",
+	})
+	if err != nil {
+		logger.Error("error loading templates", "error", err)
+		panic(err)
+	}
+
 	serverConfig := &gsk.ServerConfig{
-		Port:   port,
-		Logger: logger,
+		Port:           port,
+		Logger:         logger,
+		StaticFS:       public.AssetsFS(),
+		TemplateEngine: templateEngine,
 	}
 
 	server := gsk.New(serverConfig)
 
-	rateLimiter := svrmw.RateLimiter()
-	server.Use(rateLimiter)
+	server.Use(middleware.RequestID())
+	server.Use(middleware.TraceContext)
+	server.Use(middleware.Metrics())
 	server.Use(middleware.RequestLogger)
 	server.Use(middleware.CORS(middleware.CORSConfig{
 		AllowAll: true,
@@ -34,6 +49,20 @@ func StartHttpServer(port string) (*gsk.Server, chan bool) {
 
 	routing.SetupTemplateRoutes(server)
 	routing.SetupApiRoutes(server)
+	server.MetricsHandler("/metrics")
+	server.HealthChecks(gsk.HealthCheck{Name: "db", Check: health.SQL(db.GetSqliteConnection())})
+	// set SwaggerUIPath to serve a Swagger UI page, eg: /docs, it loads the swagger-ui scripts from a CDN by default
+	server.OpenAPI(gsk.OpenAPIConfig{
+		Title: "stktemplate",
+	})
+
+	return server
+}
+
+func StartHttpServer(port string) (*gsk.Server, chan bool) {
+
+	logger := infra.GetLogger()
+	server := NewHttpServer(port)
 
 	server.Start()
 
@@ -0,0 +1,44 @@
+/*
+Copyright © 2023 Adharsh M dev@adharsh.in
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/adharshmk96/stk/pkg/commands"
+	"github.com/spf13/cobra"
+)
+
+// routesCmd represents the routes command
+var routesCmd = &cobra.Command{
+	Use:   "routes",
+	Short: "list the routes registered in the project server.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := os.Chdir(workDir); err != nil {
+			fmt.Printf("error while changing directory: %s\n", err)
+			return
+		}
+
+		goCmd := commands.NewGoCmd()
+		if !goCmd.IsMod() {
+			fmt.Println("go.mod not found, run the command from an stk project directory.")
+			return
+		}
+
+		// the project prints its route table with its own routes command
+		output, err := goCmd.RunCmd("run", ".", "routes")
+		if err != nil {
+			fmt.Printf("error while listing routes: %s", err)
+			return
+		}
+		fmt.Print(output)
+	},
+}
+
+func init() {
+	routesCmd.Flags().StringVarP(&workDir, "workdir", "w", ".", "project directory")
+
+	rootCmd.AddCommand(routesCmd)
+}
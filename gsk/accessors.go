@@ -0,0 +1,137 @@
+package gsk
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Typed accessors for the path params, query params and headers
+// invalid values are returned as ValidationErrors, so they can be passed to ErrorResponse
+// usage example:
+//
+//	page, err := c.QueryInt("page", 1)
+//	if err != nil {
+//		c.ErrorResponse(err)
+//		return
+//	}
+
+// ParamInt returns the path param as an integer, the param is required
+func (c *Context) ParamInt(key string) (int, error) {
+	var value int
+	err := parseValue("path", key, c.Param(key), true, &value, "")
+	return value, err
+}
+
+// QueryInt returns the query param as an integer, or the default value if it is not set
+func (c *Context) QueryInt(key string, defaultValue int) (int, error) {
+	value := defaultValue
+	err := parseValue("query", key, c.QueryParam(key), false, &value, "")
+	return value, err
+}
+
+// QueryBool returns the query param as a boolean, or the default value if it is not set
+// accepts 1, t, true, 0, f, false in any case
+func (c *Context) QueryBool(key string, defaultValue bool) (bool, error) {
+	value := defaultValue
+	err := parseValue("query", key, c.QueryParam(key), false, &value, "")
+	return value, err
+}
+
+// QueryTime returns the query param as a time in the layout, default time.RFC3339
+// returns the zero time if it is not set
+func (c *Context) QueryTime(key string, layout string) (time.Time, error) {
+	var value time.Time
+	err := parseValue("query", key, c.QueryParam(key), false, &value, layout)
+	return value, err
+}
+
+// QueryList returns the values of the query param, repeated and comma separated values are combined
+// eg: ?tag=a&tag=b,c returns [a b c]
+func (c *Context) QueryList(key string) []string {
+	var values []string
+	for _, value := range c.Request.URL.Query()[key] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// Header returns the value of the request header
+func (c *Context) Header(key string) string {
+	return c.Request.Header.Get(key)
+}
+
+// ClientIP returns the IP address of the client
+// X-Forwarded-For and X-Real-IP are used only when the request comes from a proxy in ServerConfig.TrustedProxies,
+// the address is the rightmost X-Forwarded-For entry which is not a trusted proxy
+func (c *Context) ClientIP() string {
+	remoteIP := c.Request.RemoteAddr
+	if host, _, err := net.SplitHostPort(remoteIP); err == nil {
+		remoteIP = host
+	}
+
+	if c.config == nil || !c.config.isTrustedProxy(remoteIP) {
+		return remoteIP
+	}
+
+	if forwardedFor := c.Request.Header.Values("X-Forwarded-For"); len(forwardedFor) > 0 {
+		ips := strings.Split(strings.Join(forwardedFor, ","), ",")
+		for i := len(ips) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(ips[i])
+			if net.ParseIP(ip) == nil {
+				break
+			}
+			if i == 0 || !c.config.isTrustedProxy(ip) {
+				return ip
+			}
+		}
+	}
+
+	if realIP := strings.TrimSpace(c.Request.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return remoteIP
+}
+
+// Scheme returns the scheme the client used for the request, http or https
+// X-Forwarded-Proto is used only when the request comes from a proxy in ServerConfig.TrustedProxies
+func (c *Context) Scheme() string {
+	remoteIP := c.Request.RemoteAddr
+	if host, _, err := net.SplitHostPort(remoteIP); err == nil {
+		remoteIP = host
+	}
+
+	if c.config != nil && c.config.isTrustedProxy(remoteIP) {
+		proto, _, _ := strings.Cut(c.Request.Header.Get("X-Forwarded-Proto"), ",")
+		if proto = strings.ToLower(strings.TrimSpace(proto)); proto == "http" || proto == "https" {
+			return proto
+		}
+	}
+
+	if c.Request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// parseValue parses the raw value into the target with the bind conversions
+// an empty value keeps the target unchanged, or is an error if required
+func parseValue(source string, key string, raw string, required bool, target interface{}, layout string) error {
+	if raw == "" {
+		if required {
+			return ValidationErrors{{Field: key, Source: source, Rule: "required", Message: "is required"}}
+		}
+		return nil
+	}
+
+	if err := setScalarValue(reflect.ValueOf(target).Elem(), raw, layout); err != nil {
+		return ValidationErrors{{Field: key, Source: source, Rule: "type", Message: err.Error()}}
+	}
+	return nil
+}
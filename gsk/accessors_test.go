@@ -0,0 +1,209 @@
+package gsk_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_TypedAccessors(t *testing.T) {
+	t.Run("ParamInt parses the path param", func(t *testing.T) {
+		s := gsk.New()
+		s.Get("/users/:id", func(c *gsk.Context) {
+			id, err := c.ParamInt("id")
+			if err != nil {
+				c.ErrorResponse(err)
+				return
+			}
+			c.Status(http.StatusOK).JSONResponse(gsk.Map{"id": id})
+		})
+
+		rr, _ := s.Test("GET", "/users/42", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"id": 42}`, rr.Body.String())
+
+		rr, _ = s.Test("GET", "/users/abc", nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"error": "validation_failed", "fields": [{"field": "id", "source": "path", "rule": "type", "message": "must be an integer"}]}`, rr.Body.String())
+	})
+
+	t.Run("query accessors return the defaults for missing values", func(t *testing.T) {
+		var page int
+		var active bool
+		var since time.Time
+		var errs []error
+
+		s := gsk.New()
+		s.Get("/users", func(c *gsk.Context) {
+			var err error
+			page, err = c.QueryInt("page", 1)
+			errs = append(errs, err)
+			active, err = c.QueryBool("active", true)
+			errs = append(errs, err)
+			since, err = c.QueryTime("since", "2006-01-02")
+			errs = append(errs, err)
+		})
+
+		s.Test("GET", "/users", nil)
+		assert.Equal(t, 1, page)
+		assert.True(t, active)
+		assert.True(t, since.IsZero())
+		assert.Equal(t, []error{nil, nil, nil}, errs)
+
+		errs = nil
+		s.Test("GET", "/users?page=3&active=false&since=2023-10-01", nil)
+		assert.Equal(t, 3, page)
+		assert.False(t, active)
+		assert.Equal(t, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), since)
+		assert.Equal(t, []error{nil, nil, nil}, errs)
+	})
+
+	t.Run("query accessors return validation errors for invalid values", func(t *testing.T) {
+		var errs []error
+
+		s := gsk.New()
+		s.Get("/users", func(c *gsk.Context) {
+			_, err := c.QueryInt("page", 1)
+			errs = append(errs, err)
+			_, err = c.QueryBool("active", true)
+			errs = append(errs, err)
+			_, err = c.QueryTime("since", "")
+			errs = append(errs, err)
+		})
+
+		s.Test("GET", "/users?page=two&active=maybe&since=yesterday", nil)
+
+		expected := []string{
+			"validation_failed: page must be an integer",
+			"validation_failed: active must be a boolean",
+			"validation_failed: since must be a time in the format 2006-01-02T15:04:05Z07:00",
+		}
+		for i, err := range errs {
+			assert.True(t, errors.Is(err, gsk.ErrValidation))
+			assert.EqualError(t, err, expected[i])
+		}
+	})
+
+	t.Run("QueryList combines repeated and comma separated values", func(t *testing.T) {
+		var tags []string
+
+		s := gsk.New()
+		s.Get("/posts", func(c *gsk.Context) {
+			tags = c.QueryList("tag")
+		})
+
+		s.Test("GET", "/posts?tag=go&tag=web,%20api&tag=", nil)
+		assert.Equal(t, []string{"go", "web", "api"}, tags)
+
+		s.Test("GET", "/posts", nil)
+		assert.Nil(t, tags)
+	})
+
+	t.Run("Header returns the request header", func(t *testing.T) {
+		var header string
+
+		s := gsk.New()
+		s.Get("/", func(c *gsk.Context) {
+			header = c.Header("X-Tenant")
+		})
+
+		s.Test("GET", "/", nil, gsk.TestParams{Headers: map[string]string{"x-tenant": "acme"}})
+		assert.Equal(t, "acme", header)
+	})
+}
+
+func TestContext_ClientIP(t *testing.T) {
+	clientIP := func(config *gsk.ServerConfig, remoteAddr string, headers map[string]string) string {
+		var ip string
+		s := gsk.New(config)
+		s.Get("/", func(c *gsk.Context) {
+			ip = c.ClientIP()
+		})
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = remoteAddr
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		s.ServeHTTP(httptest.NewRecorder(), req)
+		return ip
+	}
+
+	trusted := &gsk.ServerConfig{TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1", "::1"}}
+
+	testCases := []struct {
+		name       string
+		config     *gsk.ServerConfig
+		remoteAddr string
+		headers    map[string]string
+		expected   string
+	}{
+		{"remote address without proxies", &gsk.ServerConfig{}, "203.0.113.5:5123", nil, "203.0.113.5"},
+		{"ignores forwarded headers from untrusted clients", &gsk.ServerConfig{}, "203.0.113.5:5123", map[string]string{"X-Forwarded-For": "1.2.3.4", "X-Real-IP": "1.2.3.4"}, "203.0.113.5"},
+		{"forwarded for from a trusted proxy", trusted, "10.0.0.2:80", map[string]string{"X-Forwarded-For": "198.51.100.7"}, "198.51.100.7"},
+		{"skips trusted proxies in the chain", trusted, "10.0.0.2:80", map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.7, 10.1.1.1, 192.168.1.1"}, "198.51.100.7"},
+		{"leftmost address when all are trusted", trusted, "10.0.0.2:80", map[string]string{"X-Forwarded-For": "10.1.1.1, 10.2.2.2"}, "10.1.1.1"},
+		{"real ip from a trusted proxy", trusted, "[::1]:80", map[string]string{"X-Real-IP": "198.51.100.8"}, "198.51.100.8"},
+		{"invalid forwarded for falls back to the remote address", trusted, "10.0.0.2:80", map[string]string{"X-Forwarded-For": "unknown"}, "10.0.0.2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, clientIP(tc.config, tc.remoteAddr, tc.headers))
+		})
+	}
+
+	t.Run("panics for invalid trusted proxies", func(t *testing.T) {
+		assert.Panics(t, func() {
+			gsk.New(&gsk.ServerConfig{TrustedProxies: []string{"proxy.local"}})
+		})
+	})
+}
+
+func TestContext_Scheme(t *testing.T) {
+	scheme := func(config *gsk.ServerConfig, target string, remoteAddr string, headers map[string]string) string {
+		var scheme string
+		s := gsk.New(config)
+		s.Get("/", func(c *gsk.Context) {
+			scheme = c.Scheme()
+		})
+
+		req := httptest.NewRequest("GET", target, nil)
+		req.RemoteAddr = remoteAddr
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		s.ServeHTTP(httptest.NewRecorder(), req)
+		return scheme
+	}
+
+	trusted := &gsk.ServerConfig{TrustedProxies: []string{"10.0.0.0/8"}}
+	forwardedHTTPS := map[string]string{"X-Forwarded-Proto": "https"}
+
+	testCases := []struct {
+		name       string
+		config     *gsk.ServerConfig
+		target     string
+		remoteAddr string
+		headers    map[string]string
+		expected   string
+	}{
+		{"plain request", &gsk.ServerConfig{}, "http://example.com/", "203.0.113.5:5123", nil, "http"},
+		{"tls request", &gsk.ServerConfig{}, "https://example.com/", "203.0.113.5:5123", nil, "https"},
+		{"ignores forwarded proto from untrusted clients", &gsk.ServerConfig{}, "http://example.com/", "203.0.113.5:5123", forwardedHTTPS, "http"},
+		{"forwarded proto from a trusted proxy", trusted, "http://example.com/", "10.0.0.2:80", forwardedHTTPS, "https"},
+		{"first forwarded proto of the chain", trusted, "https://example.com/", "10.0.0.2:80", map[string]string{"X-Forwarded-Proto": "http, https"}, "http"},
+		{"invalid forwarded proto is ignored", trusted, "https://example.com/", "10.0.0.2:80", map[string]string{"X-Forwarded-Proto": "ftp"}, "https"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, scheme(tc.config, tc.target, tc.remoteAddr, tc.headers))
+		})
+	}
+}
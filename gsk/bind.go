@@ -0,0 +1,266 @@
+package gsk
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// binding sources in the order of precedence
+var bindSources = []string{"path", "query", "form", "header"}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textUnmarshalType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Bind binds the request data into the struct pointed by v, and validates it
+// Values are bound according to the struct tags
+// - json:"name"     : from the JSON body
+// - form:"name"     : from the url encoded or multipart form body
+// - path:"id"       : from the path parameters
+// - query:"page"    : from the query parameters
+// - header:"X-Name" : from the request headers
+// - default:"10"    : used when the value is not present in the request
+// - time_format:"2006-01-02" : layout for time.Time fields, default RFC3339
+// - validate:"required,min=1,email" : validation rules, see Validate
+// Slices are bound from repeated or comma separated values
+// Returns ValidationErrors if any field is invalid, ErrInvalidJSON or ErrInvalidForm for malformed bodies
+func (c *Context) Bind(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("gsk: bind target must be a pointer to a struct, got %T", v)
+	}
+
+	errs, err := c.bindBody(v)
+	if err != nil {
+		return err
+	}
+
+	errs = append(errs, c.bindFields(rv.Elem())...)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return Validate(v)
+}
+
+// bindBody decodes the JSON body or parses the form body based on the content type
+func (c *Context) bindBody(v interface{}) (ValidationErrors, error) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+	switch {
+	case mediaType == MIMEJSON || strings.HasSuffix(mediaType, "+json"):
+		body, err := c.readBody()
+		if err != nil {
+			return nil, err
+		}
+		if len(body) == 0 {
+			return nil, nil
+		}
+
+		err = decodeJSON(body, v, c.jsonDecodeOptions(nil))
+		var validationErrors ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return validationErrors, nil
+		}
+		return nil, err
+
+	case mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data":
+		var err error
+		if mediaType == "multipart/form-data" {
+			err = c.Request.ParseMultipartForm(c.uploadConfig().MaxMemory)
+		} else {
+			err = c.Request.ParseForm()
+		}
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, ErrBodyTooLarge
+		}
+		if err != nil {
+			return nil, ErrInvalidForm
+		}
+	}
+
+	return nil, nil
+}
+
+// bindFields binds the path, query, form and header values into the struct fields
+func (c *Context) bindFields(rv reflect.Value) ValidationErrors {
+	var errs ValidationErrors
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		value := rv.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			errs = append(errs, c.bindFields(value)...)
+			continue
+		}
+
+		source, name, values := c.lookupValues(field)
+		if len(values) == 0 {
+			defaultValue, ok := field.Tag.Lookup("default")
+			if !ok || !value.IsZero() {
+				continue
+			}
+			values = []string{defaultValue}
+		}
+
+		if err := setFieldValue(value, values, field.Tag.Get("time_format")); err != nil {
+			if name == "" {
+				name = fieldName(field)
+			}
+			errs = append(errs, FieldError{
+				Field:   name,
+				Source:  source,
+				Rule:    "type",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	return errs
+}
+
+// lookupValues returns the values of the first binding source of the field present in the request
+func (c *Context) lookupValues(field reflect.StructField) (source string, name string, values []string) {
+	for _, source := range bindSources {
+		name, ok := field.Tag.Lookup(source)
+		if !ok || name == "" || name == "-" {
+			continue
+		}
+
+		switch source {
+		case "path":
+			if value := c.Param(name); value != "" {
+				return source, name, []string{value}
+			}
+		case "query":
+			if values := c.Request.URL.Query()[name]; len(values) > 0 {
+				return source, name, values
+			}
+		case "form":
+			if values := c.Request.PostForm[name]; len(values) > 0 {
+				return source, name, values
+			}
+		case "header":
+			if values := c.Request.Header.Values(name); len(values) > 0 {
+				return source, name, values
+			}
+		}
+	}
+
+	return "", "", nil
+}
+
+// setFieldValue converts the string values into the type of the field
+func setFieldValue(value reflect.Value, values []string, timeFormat string) error {
+	if value.Kind() == reflect.Pointer {
+		elem := reflect.New(value.Type().Elem())
+		if err := setFieldValue(elem.Elem(), values, timeFormat); err != nil {
+			return err
+		}
+		value.Set(elem)
+		return nil
+	}
+
+	if value.Kind() == reflect.Slice && value.Type().Elem().Kind() != reflect.Uint8 && !value.Addr().Type().Implements(textUnmarshalType) {
+		var items []string
+		for _, v := range values {
+			for _, item := range strings.Split(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		}
+
+		slice := reflect.MakeSlice(value.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setFieldValue(slice.Index(i), []string{item}, timeFormat); err != nil {
+				return err
+			}
+		}
+		value.Set(slice)
+		return nil
+	}
+
+	return setScalarValue(value, values[0], timeFormat)
+}
+
+func setScalarValue(value reflect.Value, raw string, timeFormat string) error {
+	if value.CanAddr() && value.Addr().Type().Implements(textUnmarshalType) && value.Type() != timeType {
+		if err := value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("must be a valid %s", value.Type().Name())
+		}
+		return nil
+	}
+
+	switch value.Type() {
+	case timeType:
+		if timeFormat == "" {
+			timeFormat = time.RFC3339
+		}
+		t, err := time.Parse(timeFormat, raw)
+		if err != nil {
+			return fmt.Errorf("must be a time in the format %s", timeFormat)
+		}
+		value.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return errors.New("must be a duration")
+		}
+		value.SetInt(int64(d))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("must be a boolean")
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return errors.New("must be a positive integer")
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		value.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,223 @@
+package gsk_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+type Pagination struct {
+	Page    int `query:"page" default:"1" validate:"min=1"`
+	PerPage int `query:"per_page" default:"20" validate:"max=100"`
+}
+
+type bindRequest struct {
+	Pagination
+
+	ID      int64         `path:"id"`
+	Tenant  string        `header:"X-Tenant" validate:"required"`
+	Active  *bool         `query:"active"`
+	Tags    []string      `query:"tag"`
+	IDs     []int         `query:"ids"`
+	Since   time.Time     `query:"since" time_format:"2006-01-02"`
+	Timeout time.Duration `query:"timeout" default:"5s"`
+
+	Name  string `json:"name" validate:"required,min=2"`
+	Email string `json:"email" validate:"email"`
+}
+
+func newBindServer(t *testing.T, handler func(c *gsk.Context, req *bindRequest, err error)) *gsk.Server {
+	s := gsk.New()
+	s.Post("/users/:id", func(c *gsk.Context) {
+		var req bindRequest
+		err := c.Bind(&req)
+		handler(c, &req, err)
+	})
+	return s
+}
+
+func TestBind(t *testing.T) {
+	jsonHeaders := map[string]string{
+		"Content-Type": "application/json",
+		"X-Tenant":     "acme",
+	}
+
+	t.Run("binds path, query, header and json values", func(t *testing.T) {
+		var bound bindRequest
+		s := newBindServer(t, func(c *gsk.Context, req *bindRequest, err error) {
+			assert.NoError(t, err)
+			bound = *req
+		})
+
+		body := `{"name": "John", "email": "john@example.com"}`
+		path := "/users/42?page=3&active=true&tag=a&tag=b&ids=1,2,3&since=2023-10-01&timeout=1m"
+		rr, _ := s.Test("POST", path, strings.NewReader(body), gsk.TestParams{Headers: jsonHeaders})
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		assert.Equal(t, int64(42), bound.ID)
+		assert.Equal(t, "acme", bound.Tenant)
+		assert.Equal(t, 3, bound.Page)
+		assert.Equal(t, 20, bound.PerPage)
+		assert.True(t, *bound.Active)
+		assert.Equal(t, []string{"a", "b"}, bound.Tags)
+		assert.Equal(t, []int{1, 2, 3}, bound.IDs)
+		assert.Equal(t, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), bound.Since)
+		assert.Equal(t, time.Minute, bound.Timeout)
+		assert.Equal(t, "John", bound.Name)
+		assert.Equal(t, "john@example.com", bound.Email)
+	})
+
+	t.Run("applies defaults for missing values", func(t *testing.T) {
+		var bound bindRequest
+		s := newBindServer(t, func(c *gsk.Context, req *bindRequest, err error) {
+			assert.NoError(t, err)
+			bound = *req
+		})
+
+		s.Test("POST", "/users/1", strings.NewReader(`{"name": "John"}`), gsk.TestParams{Headers: jsonHeaders})
+
+		assert.Equal(t, 1, bound.Page)
+		assert.Equal(t, 20, bound.PerPage)
+		assert.Equal(t, 5*time.Second, bound.Timeout)
+		assert.Nil(t, bound.Active)
+	})
+
+	t.Run("returns field errors for conversion failures", func(t *testing.T) {
+		var bindErr error
+		s := newBindServer(t, func(c *gsk.Context, req *bindRequest, err error) {
+			bindErr = err
+			c.ErrorResponse(err)
+		})
+
+		rr, _ := s.Test("POST", "/users/abc?active=maybe&since=yesterday", strings.NewReader(`{"name": "John"}`), gsk.TestParams{Headers: jsonHeaders})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.True(t, errors.Is(bindErr, gsk.ErrValidation))
+
+		var verrs gsk.ValidationErrors
+		assert.True(t, errors.As(bindErr, &verrs))
+		assert.Equal(t, gsk.ValidationErrors{
+			{Field: "id", Source: "path", Rule: "type", Message: "must be an integer"},
+			{Field: "active", Source: "query", Rule: "type", Message: "must be a boolean"},
+			{Field: "since", Source: "query", Rule: "type", Message: "must be a time in the format 2006-01-02"},
+		}, verrs)
+	})
+
+	t.Run("returns field errors for json type mismatch", func(t *testing.T) {
+		var bindErr error
+		s := newBindServer(t, func(c *gsk.Context, req *bindRequest, err error) {
+			bindErr = err
+		})
+
+		s.Test("POST", "/users/1", strings.NewReader(`{"name": 10}`), gsk.TestParams{Headers: jsonHeaders})
+
+		var verrs gsk.ValidationErrors
+		assert.True(t, errors.As(bindErr, &verrs))
+		assert.Equal(t, "name", verrs[0].Field)
+		assert.Equal(t, "must be a string", verrs[0].Message)
+	})
+
+	t.Run("renders validation errors as a 400 response", func(t *testing.T) {
+		s := newBindServer(t, func(c *gsk.Context, req *bindRequest, err error) {
+			c.ErrorResponse(err)
+		})
+
+		rr, _ := s.Test("POST", "/users/1?page=0&per_page=500", strings.NewReader(`{"name": "J", "email": "nope"}`), gsk.TestParams{
+			Headers: map[string]string{"Content-Type": "application/json"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		var response struct {
+			Error  string           `json:"error"`
+			Fields []gsk.FieldError `json:"fields"`
+		}
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, "validation_failed", response.Error)
+
+		fields := map[string]string{}
+		for _, fe := range response.Fields {
+			fields[fe.Field] = fe.Rule
+		}
+		assert.Equal(t, map[string]string{
+			"page":     "min",
+			"per_page": "max",
+			"X-Tenant": "required",
+			"name":     "min",
+			"email":    "email",
+		}, fields)
+	})
+
+	t.Run("returns invalid json for malformed body", func(t *testing.T) {
+		s := newBindServer(t, func(c *gsk.Context, req *bindRequest, err error) {
+			assert.True(t, errors.Is(err, gsk.ErrInvalidJSON))
+			c.ErrorResponse(err)
+		})
+
+		rr, _ := s.Test("POST", "/users/1", strings.NewReader(`{"name":`), gsk.TestParams{Headers: jsonHeaders})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"error": "invalid_json"}`, rr.Body.String())
+	})
+
+	t.Run("returns error for invalid bind target", func(t *testing.T) {
+		s := gsk.New()
+		s.Get("/", func(c *gsk.Context) {
+			var notStruct string
+			assert.Error(t, c.Bind(&notStruct))
+			assert.Error(t, c.Bind(bindRequest{}))
+		})
+		s.Test("GET", "/", nil)
+	})
+}
+
+func TestBindForm(t *testing.T) {
+	type signupForm struct {
+		Name      string   `form:"name" validate:"required"`
+		Age       uint8    `form:"age"`
+		Interests []string `form:"interest"`
+		Ref       string   `query:"ref" form:"ref"`
+	}
+
+	s := gsk.New()
+	s.Post("/signup", func(c *gsk.Context) {
+		var form signupForm
+		if err := c.Bind(&form); err != nil {
+			c.ErrorResponse(err)
+			return
+		}
+		c.JSONResponse(form)
+	})
+
+	t.Run("binds url encoded form", func(t *testing.T) {
+		values := url.Values{"name": {"Jane"}, "age": {"30"}, "interest": {"go", "sql"}, "ref": {"form"}}
+
+		rr, _ := s.Test("POST", "/signup?ref=query", strings.NewReader(values.Encode()), gsk.TestParams{
+			Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"Name": "Jane", "Age": 30, "Interests": ["go", "sql"], "Ref": "query"}`, rr.Body.String())
+	})
+
+	t.Run("returns errors for invalid form values", func(t *testing.T) {
+		values := url.Values{"age": {"300"}}
+
+		rr, _ := s.Test("POST", "/signup", bytes.NewBufferString(values.Encode()), gsk.TestParams{
+			Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"error": "validation_failed", "fields": [
+			{"field": "age", "source": "form", "rule": "type", "message": "must be a positive integer"}
+		]}`, rr.Body.String())
+	})
+}
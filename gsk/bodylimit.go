@@ -0,0 +1,79 @@
+package gsk
+
+import (
+	"io"
+	"net/http"
+)
+
+// BodyLimit limits the request body to the size in bytes, overriding ServerConfig.BodySizeLimit
+// the innermost limit is used, so a route can raise or lower the limit of its group, -1 disables the limit
+// reading beyond the limit fails with ErrBodyTooLarge, which ErrorResponse writes as 413 Request Entity Too Large
+// usage example:
+//
+//	server.Post("/imports", handler.Import, gsk.BodyLimit(50<<20))
+//	api.Use(gsk.BodyLimit(64<<10))
+func BodyLimit(limit int64) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			c.bodySizeLimit = limit
+			next(c)
+		}
+	}
+}
+
+// limitedBody limits the reads of the request body to the body size limit of the context
+// the limit is checked on every read, so BodyLimit can change it before the body is read
+type limitedBody struct {
+	body          io.ReadCloser
+	c             *Context
+	contentLength int64
+	read          int64
+	err           error
+}
+
+func newLimitedBody(c *Context) io.ReadCloser {
+	return &limitedBody{
+		body:          c.Request.Body,
+		c:             c,
+		contentLength: c.Request.ContentLength,
+	}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	limit := b.c.bodySizeLimit
+	if limit < 0 {
+		return b.body.Read(p)
+	}
+
+	// reject before reading when the declared length is over the limit
+	if b.read == 0 && b.contentLength > limit {
+		return 0, b.tooLarge(limit)
+	}
+
+	// read one byte more than the remaining limit to find out if the body is larger
+	if remaining := limit - b.read; int64(len(p)) > remaining+1 {
+		p = p[:remaining+1]
+	}
+
+	n, err := b.body.Read(p)
+	b.read += int64(n)
+	if b.read > limit {
+		return n - int(b.read-limit), b.tooLarge(limit)
+	}
+	return n, err
+}
+
+// tooLarge closes the connection after the response, like http.MaxBytesReader
+func (b *limitedBody) tooLarge(limit int64) error {
+	b.c.Writer.Header().Set("Connection", "close")
+	b.err = &http.MaxBytesError{Limit: limit}
+	return b.err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,141 @@
+package gsk_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+func readBodyHandler(c *gsk.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.ErrorResponse(err)
+		return
+	}
+	c.JSONResponse(gsk.Map{"size": len(body)})
+}
+
+// chunkedBody hides the length of the body, so the limit is checked while reading
+type chunkedBody struct {
+	io.Reader
+}
+
+func TestBodyLimit(t *testing.T) {
+	t.Run("limits the body in bytes", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{BodySizeLimit: 10})
+		s.Post("/", readBodyHandler)
+
+		rr, _ := s.Test("POST", "/", strings.NewReader("0123456789"))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"size": 10}`, rr.Body.String())
+
+		rr, _ = s.Test("POST", "/", strings.NewReader("0123456789a"))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+		assert.JSONEq(t, `{"error": "request_body_too_large"}`, rr.Body.String())
+		assert.Equal(t, "close", rr.Header().Get("Connection"))
+	})
+
+	t.Run("limits bodies without a content length while reading", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{BodySizeLimit: 10})
+		s.Post("/", readBodyHandler)
+
+		rr, _ := s.Test("POST", "/", chunkedBody{strings.NewReader("0123456789")})
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr, _ = s.Test("POST", "/", chunkedBody{strings.NewReader(strings.Repeat("a", 100))})
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("defaults to 1MB", func(t *testing.T) {
+		s := gsk.New()
+		s.Post("/", readBodyHandler)
+
+		rr, _ := s.Test("POST", "/", strings.NewReader(strings.Repeat("a", 1<<20)))
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr, _ = s.Test("POST", "/", strings.NewReader(strings.Repeat("a", 1<<20+1)))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("negative limit disables the limit", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{BodySizeLimit: -1})
+		s.Post("/", readBodyHandler)
+
+		rr, _ := s.Test("POST", "/", strings.NewReader(strings.Repeat("a", 2<<20)))
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("routes and groups override the server limit", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{BodySizeLimit: 10})
+		s.Post("/small", readBodyHandler)
+		s.Post("/large", readBodyHandler, gsk.BodyLimit(100))
+
+		api := s.RouteGroup("/api")
+		api.Use(gsk.BodyLimit(5))
+		api.Post("/small", readBodyHandler)
+		api.Post("/large", readBodyHandler, gsk.BodyLimit(50))
+
+		body := strings.Repeat("a", 20)
+		tests := []struct {
+			path   string
+			status int
+		}{
+			{"/small", http.StatusRequestEntityTooLarge},
+			{"/large", http.StatusOK},
+			{"/api/small", http.StatusRequestEntityTooLarge},
+			{"/api/large", http.StatusOK},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.path, func(t *testing.T) {
+				rr, _ := s.Test("POST", tt.path, strings.NewReader(body))
+				assert.Equal(t, tt.status, rr.Code)
+			})
+		}
+
+		rr, _ := s.Test("POST", "/api/small", strings.NewReader("12345"))
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("applies to DecodeJSONBody and Bind", func(t *testing.T) {
+		type request struct {
+			Name string `json:"name"`
+		}
+
+		s := gsk.New(&gsk.ServerConfig{BodySizeLimit: 16})
+		s.Post("/decode", gsk.E(func(c *gsk.Context) error {
+			var req request
+			return c.DecodeJSONBody(&req)
+		}))
+		s.Post("/bind", gsk.E(func(c *gsk.Context) error {
+			var req request
+			return c.Bind(&req)
+		}))
+
+		body := `{"name": "a long name over the limit"}`
+		for _, path := range []string{"/decode", "/bind"} {
+			rr, _ := s.Test("POST", path, strings.NewReader(body), gsk.TestParams{Headers: map[string]string{"Content-Type": "application/json"}})
+			assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+			assert.JSONEq(t, `{"error": "request_body_too_large"}`, rr.Body.String())
+		}
+	})
+
+	t.Run("applies to mounted handlers", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{BodySizeLimit: 10})
+		s.Mount("/legacy", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := io.ReadAll(r.Body); err != nil {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			}
+		}))
+
+		req := httptest.NewRequest("POST", "/legacy/import", strings.NewReader(strings.Repeat("a", 20)))
+		rr := httptest.NewRecorder()
+		s.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+}
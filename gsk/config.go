@@ -1,11 +1,20 @@
 package gsk
 
-import "github.com/adharshmk96/stk/pkg/logging"
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/adharshmk96/stk/pkg/logging"
+)
 
 const (
 	DEFAULT_PORT        = "8080"
 	DEFAULT_STATIC_PATH = "/static"
 	DEFAULT_STATIC_DIR  = "public/assets"
+
+	DEFAULT_BODY_SIZE_LIMIT   = 1 << 20 // 1 MB
+	DEFAULT_FILE_BUFFER_LIMIT = 1 << 20 // 1 MB
 )
 
 var DEFAULT_TEMPLATE_VARIABLES = map[string]interface{}{
@@ -31,7 +40,11 @@ func initConfig(config ...*ServerConfig) *ServerConfig {
 	}
 
 	if initConfig.BodySizeLimit == 0 {
-		initConfig.BodySizeLimit = 1
+		initConfig.BodySizeLimit = DEFAULT_BODY_SIZE_LIMIT
+	}
+
+	if initConfig.FileBufferLimit == 0 {
+		initConfig.FileBufferLimit = DEFAULT_FILE_BUFFER_LIMIT
 	}
 
 	if initConfig.StaticPath == "" {
@@ -42,5 +55,57 @@ func initConfig(config ...*ServerConfig) *ServerConfig {
 		initConfig.StaticDir = DEFAULT_STATIC_DIR
 	}
 
+	initConfig.trustedProxies = parseTrustedProxies(initConfig.TrustedProxies)
+
+	if initConfig.Router == nil {
+		initConfig.Router = HTTPRouter
+	}
+
+	if initConfig.ErrorHandler == nil {
+		initConfig.ErrorHandler = JSONErrorHandler
+	}
+
+	if initConfig.TemplateEngine != nil && initConfig.TemplateEngine.config.StaticPath == "" {
+		initConfig.TemplateEngine.config.StaticPath = initConfig.StaticPath
+	}
+
 	return initConfig
 }
+
+// parseTrustedProxies parses the IPs and CIDRs, panics if any of them is invalid
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				panic("gsk: invalid trusted proxy " + proxy)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			proxy += "/" + strconv.Itoa(bits)
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			panic("gsk: invalid trusted proxy " + proxy)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+func (config *ServerConfig) isTrustedProxy(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range config.trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
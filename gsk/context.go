@@ -3,6 +3,8 @@ package gsk
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"html/template"
 	"io"
 	"log/slog"
 	"net/http"
@@ -17,11 +19,20 @@ type Context struct {
 
 	// request
 	params        Params
+	hostParams    map[string]string
+	route         string
+	apiVersion    string
 	bodySizeLimit int64
 
 	// logging
 	logger *slog.Logger
 
+	// server configurations
+	config *ServerConfig
+
+	// template functions added for the request
+	templateFuncs template.FuncMap
+
 	// response
 	responseStatus  int
 	responseBody    []byte
@@ -35,8 +46,12 @@ type Map map[string]interface{}
 // Methods to handle request
 
 // Param gets the params within the path mentioned as a wildcard
+// or the params captured from the host, see Server.Host
 func (c *Context) Param(key string) string {
-	return c.params.ByName(key)
+	if value := c.params.ByName(key); value != "" {
+		return value
+	}
+	return c.hostParams[key]
 }
 
 // QueryParam gets the query parameters passed eg: /?name=value
@@ -57,46 +72,47 @@ func (c *Context) GetCookie(name string) (*http.Cookie, error) {
 	return c.Request.Cookie(name)
 }
 
-func (c *Context) DecodeJSONBody(v interface{}) error {
-	bodySizeLimit := int64(c.bodySizeLimit << 20) // 1 MB
-
+// DecodeJSONBody decodes the JSON body into v with the ServerConfig.JSONDecode options, or the options passed
+// invalid field types and unknown fields are returned as ValidationErrors with the path of the field
+// usage example:
+//
+//	err := c.DecodeJSONBody(&req, gsk.JSONDecodeOptions{DisallowUnknownFields: true})
+func (c *Context) DecodeJSONBody(v interface{}, options ...JSONDecodeOptions) error {
 	if c.Request.Body == nil {
 		return ErrInvalidJSON
 	}
 
-	// Set a maximum limit for the request body size to avoid possible malicious requests
-	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, bodySizeLimit)
-
-	// Manually check if the request body size exceeds the limit
-	body, err := io.ReadAll(c.Request.Body)
+	body, err := c.readBody()
 	if err != nil {
-		c.Writer.Header().Set("Content-Type", "application/json")
-		http.Error(c.Writer, ErrBodyTooLarge.Error(), http.StatusRequestEntityTooLarge)
-		return ErrBodyTooLarge
+		return err
 	}
 
-	// Decode the JSON body into the provided interface
-	err = json.Unmarshal(body, v)
+	return decodeJSON(body, v, c.jsonDecodeOptions(options))
+}
+
+// readBody reads the request body within the body size limit
+func (c *Context) readBody() ([]byte, error) {
+	if c.Request.Body == nil {
+		return nil, nil
+	}
 
 	defer c.Request.Body.Close()
 
-	// Check if there is an error in decoding the JSON, and return a user-friendly error message
+	// the body is limited to the body size limit, see BodyLimit
+	body, err := io.ReadAll(c.Request.Body)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return nil, ErrBodyTooLarge
+	}
 	if err != nil {
-		if err == io.EOF {
-			return ErrInvalidJSON
-		} else if _, ok := err.(*json.SyntaxError); ok {
-			return ErrInvalidJSON
-		}
-		// If the error is not an EOF or syntax error, return the original error
-		return err
+		return nil, err
 	}
 
-	return nil
+	return body, nil
 }
 
 // Methods related to response
 
-// For formdata and multipart formdata, use c.Request.ParseMultipartForm ...
 // sets response header key value
 func (c *Context) SetHeader(key string, value string) {
 	c.Writer.Header().Add(key, value)
@@ -141,17 +157,30 @@ func (c *Context) JSONResponse(data interface{}) {
 
 // TemplateResponse renders the provided template with the provided data
 // and writes it to the response writer with content type text/html
-func (c *Context) TemplateResponse(template *Tpl) {
+// If a TemplateEngine is configured, the template is rendered by the engine
+func (c *Context) TemplateResponse(tpl *Tpl) {
 	var err error
 	c.Writer.Header().Set("Content-Type", "text/html")
-	c.responseBody, err = template.Render(DEFAULT_TEMPLATE_VARIABLES)
+	if c.config.TemplateEngine != nil {
+		c.responseBody, err = c.config.TemplateEngine.render(tpl, DEFAULT_TEMPLATE_VARIABLES, c.templateFuncs)
+	} else {
+		c.responseBody, err = tpl.RenderFS(c.config.TemplateFS, DEFAULT_TEMPLATE_VARIABLES)
+	}
 	if err != nil {
+		c.logger.Error("error rendering template", "template", tpl.TemplatePath, "error", err)
 		c.responseStatus = http.StatusInternalServerError
 		c.responseBody = []byte(ErrInternalServer.Error())
 	}
 
 }
 
+// AddTemplateFuncs adds template functions for the templates rendered in this request
+// used by middlewares to provide request specific values, eg: csrfToken, t
+// functions are used only by the TemplateEngine
+func (c *Context) AddTemplateFuncs(funcs template.FuncMap) {
+	c.templateFuncs = mergeFuncMaps(c.templateFuncs, funcs)
+}
+
 // StringResponse writes the provided string to the response writer
 func (c *Context) StringResponse(data string) {
 	c.Writer.Header().Set("Content-Type", "text/plain")
@@ -163,20 +192,43 @@ func (c *Context) RawResponse(raw []byte) {
 	c.responseBody = raw
 }
 
-// TODO: support for template rendering, file response, stream response
+// For file responses, see File, Attachment, FileFromFS and Reader
 
 // Methods to get context values
 
+// Route returns the registered path of the matched route, eg: /users/:id
+func (c *Context) Route() string {
+	return c.route
+}
+
 // get the logger
 func (c *Context) Logger() *slog.Logger {
 	return c.logger
 }
 
+// SetLogger replaces the logger for the rest of the request
+// used by middlewares to add request attributes to every log line
+// eg: c.SetLogger(c.Logger().With("request_id", id))
+func (c *Context) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
 // Get the status code set for the response
 func (c *Context) GetStatusCode() int {
 	return c.responseStatus
 }
 
+// Get the response body set by the handler
+func (c *Context) GetResponseBody() []byte {
+	return c.responseBody
+}
+
+// ResponseWritten reports whether the response was already written to the client,
+// eg: a large file streamed by File, the body is then not available to the middlewares
+func (c *Context) ResponseWritten() bool {
+	return c.responseWritten
+}
+
 // returns a copy of the context, now it's safe to use
 func (c *Context) eject() Context {
 	return *c
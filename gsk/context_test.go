@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"testing"
+	"testing/fstest"
 
 	"github.com/adharshmk96/stk/gsk"
 	"github.com/adharshmk96/stk/testutils"
@@ -272,7 +273,7 @@ func TestDecodeJSONBodySizeLimit(t *testing.T) {
 		{
 			name:          "decodes valid json",
 			reqBody:       generate2MB(),
-			bodySizeLimit: 1,
+			bodySizeLimit: 1 << 20,
 			expectedErr:   gsk.ErrBodyTooLarge,
 		},
 	}
@@ -647,4 +648,26 @@ func TestTemplateResponse(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	})
+
+	t.Run("renders template from template fs", func(t *testing.T) {
+		config := &gsk.ServerConfig{
+			Port: "8888",
+			TemplateFS: fstest.MapFS{
+				"index.html": &fstest.MapFile{Data: []byte("<h1>{{ .Var.Title }}</h1>")},
+			},
+		}
+		s := gsk.New(config)
+
+		s.Get("/", func(c *gsk.Context) {
+			c.TemplateResponse(&gsk.Tpl{
+				TemplatePath: "index.html",
+				Variables:    gsk.Map{"Title": "Hello"},
+			})
+		})
+
+		rr, _ := s.Test("GET", "/", nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "<h1>Hello</h1>", rr.Body.String())
+	})
 }
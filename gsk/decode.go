@@ -0,0 +1,200 @@
+package gsk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// JSONDecodeOptions configures the decoding of JSON bodies by DecodeJSONBody and Bind
+// set the defaults in ServerConfig.JSONDecode, or pass the options to DecodeJSONBody
+type JSONDecodeOptions struct {
+	// DisallowUnknownFields rejects objects with fields not present in the target struct
+	DisallowUnknownFields bool
+	// DisallowTrailingData rejects bodies with data after the JSON value, eg: {"a": 1}{"b": 2}
+	DisallowTrailingData bool
+	// UseNumber decodes numbers into interface{} values as json.Number instead of float64
+	UseNumber bool
+}
+
+// jsonDecodeOptions returns the options passed to the call, or the server options
+func (c *Context) jsonDecodeOptions(options []JSONDecodeOptions) JSONDecodeOptions {
+	if len(options) > 0 {
+		return options[0]
+	}
+	if c.config != nil {
+		return c.config.JSONDecode
+	}
+	return JSONDecodeOptions{}
+}
+
+// decodeJSON decodes the JSON body into v
+// syntax errors are returned as ErrInvalidJSON, type errors and unknown fields as ValidationErrors
+func decodeJSON(body []byte, v interface{}, options JSONDecodeOptions) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if options.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if options.UseNumber {
+		decoder.UseNumber()
+	}
+
+	if err := decoder.Decode(v); err != nil {
+		return jsonError(err, body, v)
+	}
+
+	if options.DisallowTrailingData {
+		if _, err := decoder.Token(); err != io.EOF {
+			return &HTTPError{
+				Status:  http.StatusBadRequest,
+				Code:    ErrInvalidJSON.Error(),
+				Message: "unexpected data after the JSON value",
+				Err:     ErrInvalidJSON,
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonError converts the decoding error of the body into v into an error for the response
+func jsonError(err error, body []byte, v interface{}) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return ValidationErrors{{
+			Field:   typeErr.Field,
+			Source:  "json",
+			Rule:    "type",
+			Message: "must be " + jsonTypeName(typeErr.Type),
+		}}
+	}
+
+	// the decoder has no error type for unknown fields, eg: json: unknown field "nickname"
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return ValidationErrors{{
+			Field:   unknownFieldPath(body, reflect.TypeOf(v), strings.Trim(field, `"`)),
+			Source:  "json",
+			Rule:    "unknown",
+			Message: "is not allowed",
+		}}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &syntaxErr) {
+		return ErrInvalidJSON
+	}
+
+	return err
+}
+
+// unknownFieldPath returns the path of the unknown field in the body, eg: address.zip2
+// the decoder reports only the name of the field, so the body is walked along the target type
+func unknownFieldPath(body []byte, t reflect.Type, name string) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return name
+	}
+	if path := findUnknownField(value, t, "", name); path != "" {
+		return path
+	}
+	return name
+}
+
+// findUnknownField returns the path of the first field with the name which is not in the target type
+func findUnknownField(value interface{}, t reflect.Type, path string, name string) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	// types with custom decoding are not checked for unknown fields by the decoder
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return ""
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			var found string
+			switch t.Kind() {
+			case reflect.Map:
+				found = findUnknownField(item, t.Elem(), joinFieldPath(path, key), name)
+			case reflect.Struct:
+				field, ok := jsonField(t, key)
+				if !ok {
+					if key == name {
+						return joinFieldPath(path, key)
+					}
+					continue
+				}
+				found = findUnknownField(item, field.Type, joinFieldPath(path, jsonFieldName(field)), name)
+			}
+			if found != "" {
+				return found
+			}
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return ""
+		}
+		for _, item := range value {
+			if found := findUnknownField(item, t.Elem(), path, name); found != "" {
+				return found
+			}
+		}
+	}
+	return ""
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// jsonField returns the field of the struct decoded from the key, matched like encoding/json
+// the exact name is preferred over a case insensitive match
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	var match reflect.StructField
+	var matched bool
+	for _, field := range structFields(t) {
+		name := jsonFieldName(field)
+		if name == key {
+			return field, true
+		}
+		if !matched && name != "" && strings.EqualFold(name, key) {
+			match, matched = field, true
+		}
+	}
+	return match, matched
+}
+
+func joinFieldPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// jsonTypeName describes the JSON type expected for the Go type, eg: int -> an integer
+func jsonTypeName(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a positive integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+	return t.String()
+}
@@ -0,0 +1,138 @@
+package gsk_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+type decodeAddress struct {
+	City string `json:"city"`
+	Zip  int    `json:"zip"`
+}
+
+type decodeRequest struct {
+	Name    string         `json:"name"`
+	Tags    []string       `json:"tags"`
+	Address decodeAddress  `json:"address"`
+	Meta    map[string]any `json:"meta"`
+}
+
+func TestDecodeJSONBody_Options(t *testing.T) {
+	decodeServer := func(config *gsk.ServerConfig, options ...gsk.JSONDecodeOptions) (*gsk.Server, *decodeRequest, *error) {
+		var req decodeRequest
+		var decodeErr error
+		s := gsk.New(config)
+		s.Post("/", func(c *gsk.Context) {
+			req = decodeRequest{}
+			decodeErr = c.DecodeJSONBody(&req, options...)
+			if decodeErr != nil {
+				c.ErrorResponse(decodeErr)
+			}
+		})
+		return s, &req, &decodeErr
+	}
+
+	t.Run("decodes leniently by default", func(t *testing.T) {
+		s, req, decodeErr := decodeServer(&gsk.ServerConfig{})
+
+		rr, _ := s.Test("POST", "/", strings.NewReader(`{"name": "gopher", "unknown": 1}{"trailing": true}`))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NoError(t, *decodeErr)
+		assert.Equal(t, "gopher", req.Name)
+	})
+
+	t.Run("identifies the field path and expected type", func(t *testing.T) {
+		tests := []struct {
+			body     string
+			expected gsk.FieldError
+		}{
+			{`{"name": 10}`, gsk.FieldError{Field: "name", Source: "json", Rule: "type", Message: "must be a string"}},
+			{`{"tags": "go"}`, gsk.FieldError{Field: "tags", Source: "json", Rule: "type", Message: "must be an array"}},
+			{`{"address": {"zip": "560001"}}`, gsk.FieldError{Field: "address.zip", Source: "json", Rule: "type", Message: "must be an integer"}},
+		}
+
+		s, _, decodeErr := decodeServer(&gsk.ServerConfig{})
+		for _, tt := range tests {
+			t.Run(tt.body, func(t *testing.T) {
+				rr, _ := s.Test("POST", "/", strings.NewReader(tt.body))
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+				var verrs gsk.ValidationErrors
+				assert.True(t, errors.As(*decodeErr, &verrs))
+				assert.Equal(t, gsk.ValidationErrors{tt.expected}, verrs)
+			})
+		}
+	})
+
+	t.Run("disallows unknown fields from the server config", func(t *testing.T) {
+		s, _, _ := decodeServer(&gsk.ServerConfig{JSONDecode: gsk.JSONDecodeOptions{DisallowUnknownFields: true}})
+
+		rr, _ := s.Test("POST", "/", strings.NewReader(`{"name": "gopher", "nickname": "go"}`))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"error": "validation_failed", "fields": [{"field": "nickname", "source": "json", "rule": "unknown", "message": "is not allowed"}]}`, rr.Body.String())
+	})
+
+	t.Run("reports the path of nested unknown fields", func(t *testing.T) {
+		s, _, _ := decodeServer(&gsk.ServerConfig{JSONDecode: gsk.JSONDecodeOptions{DisallowUnknownFields: true}})
+
+		rr, _ := s.Test("POST", "/", strings.NewReader(`{"name": "gopher", "address": {"City": "kochi", "zip2": 1}}`))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"error": "validation_failed", "fields": [{"field": "address.zip2", "source": "json", "rule": "unknown", "message": "is not allowed"}]}`, rr.Body.String())
+	})
+
+	t.Run("options passed to the call override the server config", func(t *testing.T) {
+		s, req, decodeErr := decodeServer(
+			&gsk.ServerConfig{JSONDecode: gsk.JSONDecodeOptions{DisallowUnknownFields: true}},
+			gsk.JSONDecodeOptions{UseNumber: true},
+		)
+
+		rr, _ := s.Test("POST", "/", strings.NewReader(`{"nickname": "go", "meta": {"id": 12345678901234567890}}`))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NoError(t, *decodeErr)
+		assert.Equal(t, json.Number("12345678901234567890"), req.Meta["id"])
+	})
+
+	t.Run("rejects trailing data", func(t *testing.T) {
+		s, _, decodeErr := decodeServer(&gsk.ServerConfig{}, gsk.JSONDecodeOptions{DisallowTrailingData: true})
+
+		rr, _ := s.Test("POST", "/", strings.NewReader(`{"name": "gopher"}  `))
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr, _ = s.Test("POST", "/", strings.NewReader(`{"name": "gopher"} {"name": "other"}`))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.True(t, errors.Is(*decodeErr, gsk.ErrInvalidJSON))
+		assert.JSONEq(t, `{"error": "invalid_json", "message": "unexpected data after the JSON value"}`, rr.Body.String())
+	})
+
+	t.Run("returns ErrInvalidJSON for malformed bodies", func(t *testing.T) {
+		s, _, decodeErr := decodeServer(&gsk.ServerConfig{})
+
+		for _, body := range []string{`{"name":`, `{"name" "gopher"}`, ``} {
+			rr, _ := s.Test("POST", "/", strings.NewReader(body))
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.True(t, errors.Is(*decodeErr, gsk.ErrInvalidJSON))
+		}
+	})
+}
+
+func TestBind_JSONDecodeOptions(t *testing.T) {
+	var bindErr error
+	s := gsk.New(&gsk.ServerConfig{JSONDecode: gsk.JSONDecodeOptions{DisallowUnknownFields: true}})
+	s.Post("/", func(c *gsk.Context) {
+		var req decodeRequest
+		bindErr = c.Bind(&req)
+	})
+
+	s.Test("POST", "/", strings.NewReader(`{"name": "gopher", "nickname": "go"}`), gsk.TestParams{Headers: map[string]string{"Content-Type": "application/json"}})
+
+	var verrs gsk.ValidationErrors
+	assert.True(t, errors.As(bindErr, &verrs))
+	assert.Equal(t, "nickname", verrs[0].Field)
+	assert.Equal(t, "unknown", verrs[0].Rule)
+}
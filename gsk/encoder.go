@@ -0,0 +1,266 @@
+package gsk
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	MIMEJSON     = "application/json"
+	MIMEXML      = "application/xml"
+	MIMEYAML     = "application/yaml"
+	MIMEProtobuf = "application/x-protobuf"
+	MIMEMsgpack  = "application/msgpack"
+
+	MIMEProblemJSON = "application/problem+json"
+)
+
+// Encoder encodes the response data into the format of a media type
+type Encoder func(v interface{}) ([]byte, error)
+
+type encoderRegistry struct {
+	mux        sync.RWMutex
+	mediaTypes []string
+	encoders   map[string]Encoder
+}
+
+// registry of the encoders used by EncodedResponse and Negotiate
+// JSON, XML and YAML are registered by default
+var encoders = newEncoderRegistry()
+
+func newEncoderRegistry() *encoderRegistry {
+	registry := &encoderRegistry{
+		encoders: map[string]Encoder{},
+	}
+
+	registry.register(MIMEJSON, json.Marshal)
+	registry.register(MIMEXML, xml.Marshal)
+	registry.register(MIMEYAML, yaml.Marshal)
+
+	return registry
+}
+
+func (r *encoderRegistry) register(mediaType string, encoder Encoder) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if _, ok := r.encoders[mediaType]; !ok {
+		r.mediaTypes = append(r.mediaTypes, mediaType)
+	}
+	r.encoders[mediaType] = encoder
+}
+
+func (r *encoderRegistry) get(mediaType string) (Encoder, bool) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	encoder, ok := r.encoders[mediaType]
+	return encoder, ok
+}
+
+// offers returns the registered media types in the order of registration
+func (r *encoderRegistry) offers() []string {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	return append([]string{}, r.mediaTypes...)
+}
+
+// RegisterEncoder registers an encoder for the media type
+// registering an existing media type replaces its encoder
+// usage example:
+// gsk.RegisterEncoder(gsk.MIMEMsgpack, msgpack.Marshal)
+// gsk.RegisterEncoder(gsk.MIMEProtobuf, func(v interface{}) ([]byte, error) { return proto.Marshal(v.(proto.Message)) })
+func RegisterEncoder(mediaType string, encoder Encoder) {
+	encoders.register(mediaType, encoder)
+}
+
+// EncodedResponse encodes the data with the encoder registered for the media type
+// If no encoder is registered, or encoding fails, an internal server error is returned
+func (c *Context) EncodedResponse(mediaType string, data interface{}) {
+	encoder, ok := encoders.get(mediaType)
+	if !ok {
+		c.logger.Error("no encoder registered", "media_type", mediaType)
+		c.responseStatus = http.StatusInternalServerError
+		c.responseBody = []byte(ErrInternalServer.Error())
+		return
+	}
+
+	body, err := encoder(data)
+	if err != nil {
+		c.logger.Error("error encoding response", "media_type", mediaType, "error", err)
+		c.responseStatus = http.StatusInternalServerError
+		c.responseBody = []byte(ErrInternalServer.Error())
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", mediaType)
+	c.responseBody = body
+}
+
+// XMLResponse marshals the provided interface into XML and writes it to the response writer
+func (c *Context) XMLResponse(data interface{}) {
+	c.EncodedResponse(MIMEXML, data)
+}
+
+// YAMLResponse marshals the provided interface into YAML and writes it to the response writer
+func (c *Context) YAMLResponse(data interface{}) {
+	c.EncodedResponse(MIMEYAML, data)
+}
+
+// ProtoResponse encodes the data with the encoder registered for application/x-protobuf
+func (c *Context) ProtoResponse(data interface{}) {
+	c.EncodedResponse(MIMEProtobuf, data)
+}
+
+// MsgpackResponse encodes the data with the encoder registered for application/msgpack
+func (c *Context) MsgpackResponse(data interface{}) {
+	c.EncodedResponse(MIMEMsgpack, data)
+}
+
+// Negotiate encodes the data in the format preferred by the Accept header of the request
+// offers limits the media types to choose from, by default all the registered media types are offered
+// If none of the offers are acceptable, a 406 Not Acceptable response is returned
+func (c *Context) Negotiate(data interface{}, offers ...string) {
+	if len(offers) == 0 {
+		offers = encoders.offers()
+	}
+
+	c.Writer.Header().Add("Vary", "Accept")
+
+	mediaType := NegotiateContentType(c.Request.Header.Get("Accept"), offers)
+	if mediaType == "" {
+		c.responseStatus = http.StatusNotAcceptable
+		c.responseBody = []byte(ErrNotAcceptable.Error())
+		return
+	}
+
+	c.EncodedResponse(mediaType, data)
+}
+
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+// NegotiateContentType returns the offer best matching the Accept header
+// the first offer is returned if the Accept header is empty,
+// and an empty string is returned if none of the offers are acceptable
+func NegotiateContentType(accept string, offers []string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+
+	ranges := parseAccept(accept)
+
+	bestOffer := ""
+	bestQuality := 0.0
+	for _, offer := range offers {
+		quality := offerQuality(offer, ranges)
+		if quality > bestQuality {
+			bestOffer = offer
+			bestQuality = quality
+		}
+	}
+
+	return bestOffer
+}
+
+// NegotiateEncoding returns the offered content coding best matching the Accept-Encoding header, eg: gzip
+// an empty string is returned if the header is empty or none of the offers are acceptable,
+// the response is sent without a content coding then
+func NegotiateEncoding(acceptEncoding string, offers []string) string {
+	ranges := parseAccept(acceptEncoding)
+
+	bestOffer := ""
+	bestQuality := 0.0
+	for _, offer := range offers {
+		quality := encodingQuality(strings.ToLower(offer), ranges)
+		if quality > bestQuality {
+			bestOffer = offer
+			bestQuality = quality
+		}
+	}
+
+	return bestOffer
+}
+
+// encodingQuality returns the quality of the coding, or of * when the coding is not listed
+func encodingQuality(coding string, ranges []acceptRange) float64 {
+	wildcard := 0.0
+	for _, r := range ranges {
+		if r.mediaType == coding {
+			return r.quality
+		}
+		if r.mediaType == "*" {
+			wildcard = r.quality
+		}
+	}
+	return wildcard
+}
+
+// parseAccept parses the Accept header, most specific ranges first
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.TrimSpace(key) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				quality = q
+			}
+		}
+
+		ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return specificity(ranges[i].mediaType) > specificity(ranges[j].mediaType)
+	})
+
+	return ranges
+}
+
+func specificity(mediaType string) int {
+	switch {
+	case mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(mediaType, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// offerQuality returns the quality of the most specific range matching the offer
+func offerQuality(offer string, ranges []acceptRange) float64 {
+	offer = strings.ToLower(offer)
+	offerType, _, _ := strings.Cut(offer, "/")
+
+	for _, r := range ranges {
+		rangeType, rangeSubtype, _ := strings.Cut(r.mediaType, "/")
+		if r.mediaType == offer || r.mediaType == "*/*" || (rangeSubtype == "*" && rangeType == offerType) {
+			return r.quality
+		}
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+package gsk_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+type encoderPayload struct {
+	Message string `json:"message" xml:"message" yaml:"message"`
+}
+
+func TestEncodedResponses(t *testing.T) {
+	s := gsk.New()
+
+	payload := encoderPayload{Message: "hello"}
+
+	s.Get("/xml", func(c *gsk.Context) {
+		c.Status(http.StatusCreated).XMLResponse(payload)
+	})
+
+	s.Get("/yaml", func(c *gsk.Context) {
+		c.YAMLResponse(payload)
+	})
+
+	s.Get("/msgpack", func(c *gsk.Context) {
+		c.MsgpackResponse(payload)
+	})
+
+	s.Get("/proto", func(c *gsk.Context) {
+		c.ProtoResponse(payload)
+	})
+
+	s.Get("/unknown", func(c *gsk.Context) {
+		c.EncodedResponse("application/x-unknown", payload)
+	})
+
+	t.Run("xml response", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/xml", nil)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, gsk.MIMEXML, rr.Header().Get("Content-Type"))
+		assert.Equal(t, "<encoderPayload><message>hello</message></encoderPayload>", rr.Body.String())
+	})
+
+	t.Run("yaml response", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/yaml", nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, gsk.MIMEYAML, rr.Header().Get("Content-Type"))
+		assert.Equal(t, "message: hello\n", rr.Body.String())
+	})
+
+	t.Run("returns internal server error when no encoder is registered", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/unknown", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Equal(t, gsk.ErrInternalServer.Error(), rr.Body.String())
+	})
+
+	t.Run("uses registered encoder", func(t *testing.T) {
+		gsk.RegisterEncoder(gsk.MIMEMsgpack, func(v interface{}) ([]byte, error) {
+			return []byte("packed:" + v.(encoderPayload).Message), nil
+		})
+
+		rr, _ := s.Test("GET", "/msgpack", nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, gsk.MIMEMsgpack, rr.Header().Get("Content-Type"))
+		assert.Equal(t, "packed:hello", rr.Body.String())
+	})
+
+	t.Run("returns internal server error when encoding fails", func(t *testing.T) {
+		gsk.RegisterEncoder(gsk.MIMEProtobuf, func(v interface{}) ([]byte, error) {
+			return nil, errors.New("not a proto message")
+		})
+
+		rr, _ := s.Test("GET", "/proto", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
+
+func TestNegotiate(t *testing.T) {
+	s := gsk.New()
+
+	payload := encoderPayload{Message: "hello"}
+
+	s.Get("/", func(c *gsk.Context) {
+		c.Negotiate(payload)
+	})
+
+	s.Get("/limited", func(c *gsk.Context) {
+		c.Negotiate(payload, gsk.MIMEJSON, gsk.MIMEYAML)
+	})
+
+	testCases := []struct {
+		name        string
+		path        string
+		accept      string
+		status      int
+		contentType string
+	}{
+		{name: "defaults to json without accept header", path: "/", accept: "", status: http.StatusOK, contentType: gsk.MIMEJSON},
+		{name: "picks xml", path: "/", accept: "application/xml", status: http.StatusOK, contentType: gsk.MIMEXML},
+		{name: "picks highest quality", path: "/", accept: "application/json;q=0.5, application/yaml;q=0.9", status: http.StatusOK, contentType: gsk.MIMEYAML},
+		{name: "wildcard picks first offer", path: "/", accept: "*/*", status: http.StatusOK, contentType: gsk.MIMEJSON},
+		{name: "specific range overrides wildcard", path: "/", accept: "*/*;q=0.1, application/xml", status: http.StatusOK, contentType: gsk.MIMEXML},
+		{name: "subtype wildcard", path: "/limited", accept: "application/*", status: http.StatusOK, contentType: gsk.MIMEJSON},
+		{name: "zero quality is not acceptable", path: "/limited", accept: "application/json;q=0", status: http.StatusNotAcceptable},
+		{name: "unknown type is not acceptable", path: "/", accept: "text/csv", status: http.StatusNotAcceptable},
+		{name: "offers are limited", path: "/limited", accept: "application/xml", status: http.StatusNotAcceptable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr, _ := s.Test("GET", tc.path, nil, gsk.TestParams{
+				Headers: map[string]string{"Accept": tc.accept},
+			})
+
+			assert.Equal(t, tc.status, rr.Code)
+			assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+			if tc.status == http.StatusOK {
+				assert.Equal(t, tc.contentType, rr.Header().Get("Content-Type"))
+			} else {
+				assert.Equal(t, gsk.ErrNotAcceptable.Error(), rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	offers := []string{"br", "gzip", "deflate"}
+
+	testCases := []struct {
+		name           string
+		acceptEncoding string
+		expected       string
+	}{
+		{name: "no header", acceptEncoding: "", expected: ""},
+		{name: "single coding", acceptEncoding: "gzip", expected: "gzip"},
+		{name: "first offer on equal quality", acceptEncoding: "deflate, gzip", expected: "gzip"},
+		{name: "highest quality", acceptEncoding: "gzip;q=0.5, deflate;q=0.8", expected: "deflate"},
+		{name: "wildcard", acceptEncoding: "*", expected: "br"},
+		{name: "wildcard with excluded coding", acceptEncoding: "br;q=0, *;q=0.5", expected: "gzip"},
+		{name: "case insensitive", acceptEncoding: "GZIP", expected: "gzip"},
+		{name: "identity only", acceptEncoding: "identity", expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, gsk.NegotiateEncoding(tc.acceptEncoding, offers))
+		})
+	}
+}
@@ -1,9 +1,259 @@
 package gsk
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
 
 var (
 	ErrInvalidJSON    = errors.New("invalid_json")
+	ErrInvalidForm    = errors.New("invalid_form")
+	ErrValidation     = errors.New("validation_failed")
 	ErrInternalServer = errors.New("internal_server_error")
 	ErrBodyTooLarge   = errors.New("request_body_too_large")
+	ErrFileNotFound   = errors.New("file_not_found")
+	ErrNotAcceptable  = errors.New("not_acceptable")
+
+	ErrFileTooLarge        = errors.New("file_too_large")
+	ErrUnsupportedFileType = errors.New("unsupported_file_type")
+
+	ErrTemplateNotFound = errors.New("template_not_found")
 )
+
+// HandlerFuncE is a handler which returns an error
+// the returned error is written as the response by the ErrorHandler, use E to register it
+type HandlerFuncE func(*Context) error
+
+// E converts a HandlerFuncE into a HandlerFunc
+// routes of E are listed as gsk.E in the route table, use Route.HandlerName to name them
+// usage example:
+// server.Get("/users/:id", gsk.E(func(gc *gsk.Context) error { return gsk.NewHTTPError(404, "user_not_found", "user not found") }))
+func E(handler HandlerFuncE) HandlerFunc {
+	return func(c *Context) {
+		if err := handler(c); err != nil {
+			c.ErrorResponse(err)
+		}
+	}
+}
+
+// ErrorHandler writes the response for an error returned from a handler
+// set it in ServerConfig.ErrorHandler, default is JSONErrorHandler
+type ErrorHandler func(c *Context, err error)
+
+// StatusCoder is implemented by errors which know their http status
+// domain errors can implement it to be mapped to a response without depending on HTTPError
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// HTTPError is an error with the http status, machine readable code, message and details of the response
+type HTTPError struct {
+	Status  int
+	Code    string
+	Message string
+	Details interface{}
+	// Err is the underlying error, it is logged and never written to the response
+	Err error
+}
+
+// NewHTTPError creates a new HTTPError
+// usage example:
+// ErrUserNotFound = gsk.NewHTTPError(http.StatusNotFound, "user_not_found", "user not found")
+func NewHTTPError(status int, code string, message string) *HTTPError {
+	return &HTTPError{
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *HTTPError) Error() string {
+	message := e.Code
+	if e.Message != "" {
+		message += ": " + e.Message
+	}
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports errors with the same status and code as equal, so wrapped copies match the original
+func (e *HTTPError) Is(target error) bool {
+	t, ok := target.(*HTTPError)
+	return ok && t.Status == e.Status && t.Code == e.Code
+}
+
+func (e *HTTPError) StatusCode() int {
+	if e.Status == 0 {
+		return http.StatusInternalServerError
+	}
+	return e.Status
+}
+
+// WithDetails returns a copy of the error with the details
+func (e *HTTPError) WithDetails(details interface{}) *HTTPError {
+	err := *e
+	err.Details = details
+	return &err
+}
+
+// Wrap returns a copy of the error wrapping the underlying error
+func (e *HTTPError) Wrap(cause error) *HTTPError {
+	err := *e
+	err.Err = cause
+	return &err
+}
+
+// ErrorResponse writes the error response using the ErrorHandler of the server
+// with the default JSONErrorHandler
+// - HTTPError : status {"error": code, "message": message, "details": details}
+// - ValidationErrors : 400 {"error": "validation_failed", "fields": [...]}
+// - ErrInvalidJSON, ErrInvalidForm : 400 {"error": "invalid_json"}
+// - ErrBodyTooLarge, http.MaxBytesError : 413 {"error": "request_body_too_large"}
+// - StatusCoder : status {"error": "not_found"}
+// - any other error : 500 {"error": "internal_server_error"}, the error is logged
+func (c *Context) ErrorResponse(err error) {
+	if c.config != nil && c.config.ErrorHandler != nil {
+		c.config.ErrorHandler(c, err)
+		return
+	}
+	JSONErrorHandler(c, err)
+}
+
+// ToHTTPError converts the error into an HTTPError
+// Errors which are not HTTPError, known gsk errors or StatusCoder are converted to 500 internal_server_error
+func ToHTTPError(err error) *HTTPError {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+
+	var validationErrors ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return &HTTPError{
+			Status:  validationErrors.StatusCode(),
+			Code:    ErrValidation.Error(),
+			Details: []FieldError(validationErrors),
+			Err:     err,
+		}
+	}
+
+	// reading the body beyond the limit, eg: io.ReadAll(c.Request.Body)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return &HTTPError{Status: http.StatusRequestEntityTooLarge, Code: ErrBodyTooLarge.Error(), Err: err}
+	}
+
+	for _, sentinel := range sentinelErrors {
+		if errors.Is(err, sentinel.err) {
+			return &HTTPError{Status: sentinel.status, Code: sentinel.err.Error(), Err: err}
+		}
+	}
+
+	status := http.StatusInternalServerError
+	var statusCoder StatusCoder
+	if errors.As(err, &statusCoder) {
+		status = statusCoder.StatusCode()
+	}
+	return &HTTPError{Status: status, Code: statusCode(status), Err: err}
+}
+
+// status of the errors returned by gsk
+var sentinelErrors = []struct {
+	err    error
+	status int
+}{
+	{ErrInvalidJSON, http.StatusBadRequest},
+	{ErrInvalidForm, http.StatusBadRequest},
+	{ErrBodyTooLarge, http.StatusRequestEntityTooLarge},
+	{ErrFileNotFound, http.StatusNotFound},
+	{ErrNotAcceptable, http.StatusNotAcceptable},
+	{ErrFileTooLarge, http.StatusRequestEntityTooLarge},
+	{ErrUnsupportedFileType, http.StatusUnsupportedMediaType},
+}
+
+// statusCode returns the code for the status, eg: 404 -> not_found
+func statusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return ErrInternalServer.Error()
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
+}
+
+// JSONErrorHandler is the default ErrorHandler, it writes the error as {"error": code}
+// message and details are included when present, server errors are logged
+func JSONErrorHandler(c *Context, err error) {
+	httpErr := logHTTPError(c, err)
+
+	var validationErrors ValidationErrors
+	if errors.As(err, &validationErrors) {
+		c.Status(httpErr.StatusCode()).JSONResponse(validationErrors)
+		return
+	}
+
+	response := Map{"error": httpErr.Code}
+	if httpErr.Message != "" {
+		response["message"] = httpErr.Message
+	}
+	if httpErr.Details != nil {
+		response["details"] = httpErr.Details
+	}
+	c.Status(httpErr.StatusCode()).JSONResponse(response)
+}
+
+// ProblemJSONErrorHandler writes the error as RFC 7807 application/problem+json
+// {"type": "about:blank", "title": "Not Found", "status": 404, "detail": message, "instance": path, "code": code}
+// details are added as the "details" member, validation errors as the "fields" member
+// usage example:
+// gsk.New(&gsk.ServerConfig{ErrorHandler: gsk.ProblemJSONErrorHandler})
+func ProblemJSONErrorHandler(c *Context, err error) {
+	httpErr := logHTTPError(c, err)
+	status := httpErr.StatusCode()
+
+	problem := Map{
+		"type":     "about:blank",
+		"title":    http.StatusText(status),
+		"status":   status,
+		"instance": c.Request.URL.Path,
+		"code":     httpErr.Code,
+	}
+	if httpErr.Message != "" {
+		problem["detail"] = httpErr.Message
+	}
+
+	var validationErrors ValidationErrors
+	if errors.As(err, &validationErrors) {
+		problem["fields"] = []FieldError(validationErrors)
+	} else if httpErr.Details != nil {
+		problem["details"] = httpErr.Details
+	}
+
+	body, marshalErr := json.Marshal(problem)
+	if marshalErr != nil {
+		c.logger.Error("error encoding problem response", "error", marshalErr)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", MIMEProblemJSON)
+	c.Status(status).RawResponse(body)
+}
+
+// logHTTPError converts the error and logs it when it is a server error
+// panics are not logged again, Recover logs them with the stack trace
+func logHTTPError(c *Context, err error) *HTTPError {
+	httpErr := ToHTTPError(err)
+	var panicErr *PanicError
+	if httpErr.StatusCode() >= http.StatusInternalServerError && !errors.As(err, &panicErr) {
+		c.logger.Error("error handling request", "error", err)
+	}
+	return httpErr
+}
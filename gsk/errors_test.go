@@ -0,0 +1,147 @@
+package gsk_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+var errUserNotFound = gsk.NewHTTPError(http.StatusNotFound, "user_not_found", "user not found")
+
+type conflictError struct{}
+
+func (conflictError) Error() string   { return "already exists" }
+func (conflictError) StatusCode() int { return http.StatusConflict }
+
+func TestHTTPError(t *testing.T) {
+	t.Run("wrapped copies match the original", func(t *testing.T) {
+		cause := errors.New("sql: no rows in result set")
+		err := fmt.Errorf("get user: %w", errUserNotFound.Wrap(cause))
+
+		assert.True(t, errors.Is(err, errUserNotFound))
+		assert.True(t, errors.Is(err, cause))
+		assert.Nil(t, errUserNotFound.Err)
+		assert.Equal(t, "user_not_found: user not found: sql: no rows in result set", errUserNotFound.Wrap(cause).Error())
+	})
+
+	t.Run("converts errors to http errors", func(t *testing.T) {
+		testCases := []struct {
+			err    error
+			status int
+			code   string
+		}{
+			{errUserNotFound, http.StatusNotFound, "user_not_found"},
+			{gsk.ErrInvalidJSON, http.StatusBadRequest, "invalid_json"},
+			{fmt.Errorf("decode: %w", gsk.ErrBodyTooLarge), http.StatusRequestEntityTooLarge, "request_body_too_large"},
+			{gsk.ValidationErrors{{Field: "name", Rule: "required"}}, http.StatusBadRequest, "validation_failed"},
+			{conflictError{}, http.StatusConflict, "conflict"},
+			{errors.New("db down"), http.StatusInternalServerError, "internal_server_error"},
+			{&gsk.HTTPError{Code: "unknown"}, http.StatusInternalServerError, "unknown"},
+		}
+
+		for _, tc := range testCases {
+			httpErr := gsk.ToHTTPError(tc.err)
+			assert.Equal(t, tc.status, httpErr.StatusCode())
+			assert.Equal(t, tc.code, httpErr.Code)
+		}
+	})
+}
+
+func TestErrorHandler(t *testing.T) {
+	handlers := map[string]gsk.HandlerFuncE{
+		"/http-error": func(gc *gsk.Context) error {
+			return errUserNotFound.WithDetails(gsk.Map{"id": gc.QueryParam("id")})
+		},
+		"/status-coder": func(gc *gsk.Context) error {
+			return fmt.Errorf("create user: %w", conflictError{})
+		},
+		"/internal": func(gc *gsk.Context) error {
+			return errors.New("connection refused")
+		},
+		"/validation": func(gc *gsk.Context) error {
+			return gsk.ValidationErrors{{Field: "name", Rule: "required", Message: "is required"}}
+		},
+		"/ok": func(gc *gsk.Context) error {
+			gc.Status(http.StatusCreated).JSONResponse(gsk.Map{"message": "created"})
+			return nil
+		},
+	}
+
+	newServer := func(config *gsk.ServerConfig) *gsk.Server {
+		s := gsk.New(config)
+		for path, handler := range handlers {
+			s.Get(path, gsk.E(handler))
+		}
+		return s
+	}
+
+	t.Run("default handler writes json errors", func(t *testing.T) {
+		s := newServer(&gsk.ServerConfig{})
+
+		testCases := []struct {
+			path   string
+			status int
+			body   string
+		}{
+			{"/http-error?id=12", http.StatusNotFound, `{"error": "user_not_found", "message": "user not found", "details": {"id": "12"}}`},
+			{"/status-coder", http.StatusConflict, `{"error": "conflict"}`},
+			{"/internal", http.StatusInternalServerError, `{"error": "internal_server_error"}`},
+			{"/validation", http.StatusBadRequest, `{"error": "validation_failed", "fields": [{"field": "name", "rule": "required", "message": "is required"}]}`},
+			{"/ok", http.StatusCreated, `{"message": "created"}`},
+		}
+
+		for _, tc := range testCases {
+			rr, _ := s.Test("GET", tc.path, nil)
+			assert.Equal(t, tc.status, rr.Code, tc.path)
+			assert.JSONEq(t, tc.body, rr.Body.String(), tc.path)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		}
+	})
+
+	t.Run("problem json handler writes rfc 7807 errors", func(t *testing.T) {
+		s := newServer(&gsk.ServerConfig{ErrorHandler: gsk.ProblemJSONErrorHandler})
+
+		rr, _ := s.Test("GET", "/http-error?id=12", nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, gsk.MIMEProblemJSON, rr.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{
+			"type": "about:blank",
+			"title": "Not Found",
+			"status": 404,
+			"detail": "user not found",
+			"instance": "/http-error",
+			"code": "user_not_found",
+			"details": {"id": "12"}
+		}`, rr.Body.String())
+
+		rr, _ = s.Test("GET", "/validation", nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{
+			"type": "about:blank",
+			"title": "Bad Request",
+			"status": 400,
+			"instance": "/validation",
+			"code": "validation_failed",
+			"fields": [{"field": "name", "rule": "required", "message": "is required"}]
+		}`, rr.Body.String())
+	})
+
+	t.Run("custom handler receives the returned error", func(t *testing.T) {
+		var handled error
+		s := newServer(&gsk.ServerConfig{
+			ErrorHandler: func(c *gsk.Context, err error) {
+				handled = err
+				c.Status(http.StatusTeapot).StringResponse(err.Error())
+			},
+		})
+
+		rr, _ := s.Test("GET", "/internal", nil)
+		assert.Equal(t, http.StatusTeapot, rr.Code)
+		assert.Equal(t, "connection refused", rr.Body.String())
+		assert.EqualError(t, handled, "connection refused")
+	})
+}
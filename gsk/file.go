@@ -0,0 +1,216 @@
+package gsk
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// responseCapture is a http.ResponseWriter that captures the status and body
+// written by net/http helpers into the buffered gsk response
+// headers are written directly to the underlying writer
+type responseCapture struct {
+	c *Context
+}
+
+func (rc *responseCapture) Header() http.Header {
+	return rc.c.Writer.Header()
+}
+
+func (rc *responseCapture) WriteHeader(status int) {
+	// a status set by the handler is kept for full responses,
+	// statuses decided by net/http helpers (304, 206, 416) take precedence
+	if status != http.StatusOK || rc.c.responseStatus == 0 {
+		rc.c.responseStatus = status
+	}
+}
+
+func (rc *responseCapture) Write(b []byte) (int, error) {
+	rc.c.responseBody = append(rc.c.responseBody, b...)
+	return len(b), nil
+}
+
+// fileStreamWriter is a http.ResponseWriter that writes the status and body
+// of net/http helpers directly to the client, the response is marked as written
+type fileStreamWriter struct {
+	c *Context
+}
+
+func (fw *fileStreamWriter) Header() http.Header {
+	return fw.c.Writer.Header()
+}
+
+func (fw *fileStreamWriter) WriteHeader(status int) {
+	if status != http.StatusOK || fw.c.responseStatus == 0 {
+		fw.c.responseStatus = status
+	}
+	fw.c.responseWritten = true
+	fw.c.Writer.WriteHeader(fw.c.responseStatus)
+}
+
+func (fw *fileStreamWriter) Write(b []byte) (int, error) {
+	if !fw.c.responseWritten {
+		fw.WriteHeader(http.StatusOK)
+	}
+	return fw.c.Writer.Write(b)
+}
+
+// File writes the file at the given path as the response
+// Content-Type is detected from the extension or the content of the file
+// Last-Modified and ETag headers are set, conditional and range requests are supported
+// If the file does not exist, a 404 response is returned
+// Files up to ServerConfig.FileBufferLimit are buffered in the response like other responses,
+// larger files are streamed to the client, the middlewares see their status and headers but not the body
+func (c *Context) File(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		c.fileError(err)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		c.fileError(err)
+		return
+	}
+	if info.IsDir() {
+		c.fileError(fs.ErrNotExist)
+		return
+	}
+
+	c.serveContent(info.Name(), info.ModTime(), info.Size(), f)
+}
+
+// Attachment writes the file at the given path as a downloadable response
+// filename is used as the name of the file in the Content-Disposition header
+func (c *Context) Attachment(path string, filename string) {
+	if filename == "" {
+		filename = filepath.Base(path)
+	}
+	c.Writer.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": filename,
+	}))
+	c.File(path)
+}
+
+// FileFromFS writes the file with the given name from the file system as the response
+// It behaves like File, and can be used with embed.FS or any other fs.FS
+// Files that do not implement io.Seeker are read into memory to support range requests
+func (c *Context) FileFromFS(fsys fs.FS, name string) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		c.fileError(err)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		c.fileError(err)
+		return
+	}
+	if info.IsDir() {
+		c.fileError(fs.ErrNotExist)
+		return
+	}
+
+	// Range requests need seeking, files from fs.FS are not guaranteed to support it
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			c.fileError(err)
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+
+	c.serveContent(info.Name(), info.ModTime(), info.Size(), content)
+}
+
+// Reader writes the content of the reader as the response with the given content type
+// size is used as the Content-Length, pass -1 if the size is unknown
+// If the reader is an io.ReadSeeker, range requests are supported
+func (c *Context) Reader(contentType string, size int64, reader io.Reader) {
+	if contentType != "" {
+		c.Writer.Header().Set("Content-Type", contentType)
+	}
+
+	if seeker, ok := reader.(io.ReadSeeker); ok && size >= 0 {
+		http.ServeContent(&responseCapture{c: c}, c.Request, "", time.Time{}, seeker)
+		return
+	}
+
+	var err error
+	if size >= 0 {
+		c.Writer.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+		c.responseBody, err = io.ReadAll(io.LimitReader(reader, size))
+	} else {
+		c.responseBody, err = io.ReadAll(reader)
+	}
+	if err != nil {
+		c.Writer.Header().Del("Content-Length")
+		c.responseStatus = http.StatusInternalServerError
+		c.responseBody = []byte(ErrInternalServer.Error())
+	}
+}
+
+// serveContent writes the content using http.ServeContent
+// which takes care of content type, conditional requests and range requests
+// content larger than the FileBufferLimit is written directly to the client
+func (c *Context) serveContent(name string, modTime time.Time, size int64, content io.ReadSeeker) {
+	headers := c.Writer.Header()
+	if headers.Get("ETag") == "" {
+		etag, err := fileETag(modTime, size, content)
+		if err != nil {
+			c.fileError(err)
+			return
+		}
+		headers.Set("ETag", etag)
+	}
+
+	if c.config != nil && c.config.FileBufferLimit > 0 && size > c.config.FileBufferLimit {
+		http.ServeContent(&fileStreamWriter{c: c}, c.Request, name, modTime, content)
+		return
+	}
+	http.ServeContent(&responseCapture{c: c}, c.Request, name, modTime, content)
+}
+
+func (c *Context) fileError(err error) {
+	c.Writer.Header().Del("Content-Disposition")
+	if errors.Is(err, fs.ErrNotExist) {
+		c.responseStatus = http.StatusNotFound
+		c.responseBody = []byte(ErrFileNotFound.Error())
+		return
+	}
+	c.logger.Error("error serving file", "error", err)
+	c.responseStatus = http.StatusInternalServerError
+	c.responseBody = []byte(ErrInternalServer.Error())
+}
+
+// fileETag generates a strong ETag from the modification time and size of the file,
+// so If-Range requests can resume downloads, files without a modification time (eg: embed.FS) are hashed instead
+func fileETag(modTime time.Time, size int64, content io.ReadSeeker) (string, error) {
+	if !modTime.IsZero() {
+		return fmt.Sprintf(`"%x-%x"`, modTime.UnixNano(), size), nil
+	}
+
+	hash := fnv.New64a()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`"%x-%x"`, hash.Sum64(), size), nil
+}
@@ -0,0 +1,209 @@
+package gsk_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/adharshmk96/stk/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileResponse(t *testing.T) {
+	dir, removeDir := testutils.CreateTempDirectory(t)
+	defer removeDir()
+
+	filePath := filepath.Join(dir, "hello.txt")
+	testutils.WriteFile(t, filePath, "hello world")
+
+	s := gsk.New()
+
+	s.Get("/file", func(c *gsk.Context) {
+		c.File(filePath)
+	})
+
+	s.Get("/missing", func(c *gsk.Context) {
+		c.File(filepath.Join(dir, "missing.txt"))
+	})
+
+	s.Get("/attachment", func(c *gsk.Context) {
+		c.Attachment(filePath, "report.txt")
+	})
+
+	t.Run("serves file with content type and cache headers", func(t *testing.T) {
+		rr, err := s.Test("GET", "/file", nil)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "hello world", rr.Body.String())
+		assert.Equal(t, "text/plain; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.NotEmpty(t, rr.Header().Get("ETag"))
+		assert.NotEmpty(t, rr.Header().Get("Last-Modified"))
+		assert.Equal(t, "bytes", rr.Header().Get("Accept-Ranges"))
+	})
+
+	t.Run("returns 304 when etag matches", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/file", nil)
+		etag := rr.Header().Get("ETag")
+
+		rr, _ = s.Test("GET", "/file", nil, gsk.TestParams{
+			Headers: map[string]string{"If-None-Match": etag},
+		})
+
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+		assert.Empty(t, rr.Body.String())
+	})
+
+	t.Run("serves range of the file", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/file", nil, gsk.TestParams{
+			Headers: map[string]string{"Range": "bytes=0-4"},
+		})
+
+		assert.Equal(t, http.StatusPartialContent, rr.Code)
+		assert.Equal(t, "hello", rr.Body.String())
+		assert.Equal(t, "bytes 0-4/11", rr.Header().Get("Content-Range"))
+	})
+
+	t.Run("resumes the download with If-Range", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/file", nil)
+		etag := rr.Header().Get("ETag")
+		assert.False(t, strings.HasPrefix(etag, "W/"))
+
+		rr, _ = s.Test("GET", "/file", nil, gsk.TestParams{
+			Headers: map[string]string{"Range": "bytes=6-", "If-Range": etag},
+		})
+		assert.Equal(t, http.StatusPartialContent, rr.Code)
+		assert.Equal(t, "world", rr.Body.String())
+
+		rr, _ = s.Test("GET", "/file", nil, gsk.TestParams{
+			Headers: map[string]string{"Range": "bytes=6-", "If-Range": `"changed"`},
+		})
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "hello world", rr.Body.String())
+	})
+
+	t.Run("returns 416 for unsatisfiable range", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/file", nil, gsk.TestParams{
+			Headers: map[string]string{"Range": "bytes=100-200"},
+		})
+
+		assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, rr.Code)
+	})
+
+	t.Run("returns 404 for missing file", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/missing", nil)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, gsk.ErrFileNotFound.Error(), rr.Body.String())
+	})
+
+	t.Run("attachment sets content disposition", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/attachment", nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `attachment; filename=report.txt`, rr.Header().Get("Content-Disposition"))
+		assert.Equal(t, "hello world", rr.Body.String())
+	})
+
+	t.Run("streams files larger than the buffer limit", func(t *testing.T) {
+		var bufferedBody []byte
+		var bufferedStatus int
+		s := gsk.New(&gsk.ServerConfig{FileBufferLimit: 4})
+		s.Use(func(next gsk.HandlerFunc) gsk.HandlerFunc {
+			return func(c *gsk.Context) {
+				next(c)
+				bufferedBody, bufferedStatus = c.GetResponseBody(), c.GetStatusCode()
+			}
+		})
+		s.Get("/file", func(c *gsk.Context) {
+			c.File(filePath)
+		})
+
+		rr, _ := s.Test("GET", "/file", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "hello world", rr.Body.String())
+		assert.NotEmpty(t, rr.Header().Get("ETag"))
+		assert.Equal(t, http.StatusOK, bufferedStatus)
+		assert.Empty(t, bufferedBody)
+
+		rr, _ = s.Test("GET", "/file", nil, gsk.TestParams{
+			Headers: map[string]string{"Range": "bytes=6-"},
+		})
+		assert.Equal(t, http.StatusPartialContent, rr.Code)
+		assert.Equal(t, "world", rr.Body.String())
+		assert.Equal(t, http.StatusPartialContent, bufferedStatus)
+	})
+}
+
+func TestFileFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"assets/app.js": &fstest.MapFile{Data: []byte("console.log('hi')")},
+	}
+
+	s := gsk.New()
+
+	s.Get("/fs/*name", func(c *gsk.Context) {
+		c.FileFromFS(fsys, strings.TrimPrefix(c.Param("name"), "/"))
+	})
+
+	t.Run("serves file from fs", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/fs/assets/app.js", nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "console.log('hi')", rr.Body.String())
+		assert.Contains(t, rr.Header().Get("Content-Type"), "javascript")
+		assert.NotEmpty(t, rr.Header().Get("ETag"))
+	})
+
+	t.Run("etag is stable for files without modification time", func(t *testing.T) {
+		rr1, _ := s.Test("GET", "/fs/assets/app.js", nil)
+		rr2, _ := s.Test("GET", "/fs/assets/app.js", nil, gsk.TestParams{
+			Headers: map[string]string{"If-None-Match": rr1.Header().Get("ETag")},
+		})
+
+		assert.Equal(t, http.StatusNotModified, rr2.Code)
+	})
+
+	t.Run("returns 404 for directories and missing files", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/fs/assets", nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		rr, _ = s.Test("GET", "/fs/nope.js", nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestReaderResponse(t *testing.T) {
+	s := gsk.New()
+
+	s.Get("/seeker", func(c *gsk.Context) {
+		c.Reader("text/csv", 11, bytes.NewReader([]byte("a,b,c\n1,2,3")))
+	})
+
+	s.Get("/stream", func(c *gsk.Context) {
+		c.Status(http.StatusAccepted).Reader("text/plain", -1, io.MultiReader(strings.NewReader("foo"), strings.NewReader("bar")))
+	})
+
+	t.Run("serves seekable reader with range support", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/seeker", nil, gsk.TestParams{
+			Headers: map[string]string{"Range": "bytes=6-"},
+		})
+
+		assert.Equal(t, http.StatusPartialContent, rr.Code)
+		assert.Equal(t, "1,2,3", rr.Body.String())
+		assert.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+	})
+
+	t.Run("serves plain reader with status", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/stream", nil)
+
+		assert.Equal(t, http.StatusAccepted, rr.Code)
+		assert.Equal(t, "foobar", rr.Body.String())
+		assert.Equal(t, "text/plain", rr.Header().Get("Content-Type"))
+	})
+}
@@ -0,0 +1,194 @@
+package gsk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	HealthPath    = "/healthz"
+	ReadinessPath = "/readyz"
+
+	defaultHealthCheckTimeout = 5 * time.Second
+	defaultHealthCheckTTL     = time.Second
+)
+
+var ErrHealthCheckTimeout = errors.New("health check timed out")
+
+// HealthCheckFunc returns an error when the dependency is not healthy
+// the context is cancelled when the check times out
+type HealthCheckFunc func(ctx context.Context) error
+
+type HealthCheck struct {
+	Name  string
+	Check HealthCheckFunc
+	// Timeout of the check, default 5s
+	Timeout time.Duration
+	// CacheTTL is how long the result is reused, default 1s, negative to disable caching
+	CacheTTL time.Duration
+	// Liveness includes the check in /healthz, checks are used only by /readyz by default
+	// only add checks which are fixed by restarting the process
+	Liveness bool
+}
+
+// HealthCheckResult is the result of a check in the health response
+type HealthCheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// HealthResponse is the response of /healthz and /readyz
+type HealthResponse struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks,omitempty"`
+}
+
+const (
+	healthStatusOK           = "ok"
+	healthStatusFailed       = "failed"
+	healthStatusShuttingDown = "shutting_down"
+)
+
+// HealthChecks registers the liveness (/healthz) and readiness (/readyz) endpoints
+// /readyz runs all the checks and fails while the server is shutting down,
+// /healthz runs only the liveness checks. Checks run concurrently and the results are cached.
+// Responds with 200 when healthy and 503 otherwise
+// {"status": "ok", "checks": {"db": {"status": "ok", "duration": "1.2ms"}}}
+// usage example:
+// server.HealthChecks(gsk.HealthCheck{Name: "db", Check: health.SQL(conn)}, gsk.HealthCheck{Name: "disk", Check: health.DiskSpace("/", 1<<30)})
+func (s *Server) HealthChecks(checks ...HealthCheck) {
+	var livenessChecks []*cachedHealthCheck
+	var readinessChecks []*cachedHealthCheck
+
+	for _, check := range checks {
+		if check.Timeout <= 0 {
+			check.Timeout = defaultHealthCheckTimeout
+		}
+		if check.CacheTTL == 0 {
+			check.CacheTTL = defaultHealthCheckTTL
+		}
+
+		cached := &cachedHealthCheck{HealthCheck: check}
+		readinessChecks = append(readinessChecks, cached)
+		if check.Liveness {
+			livenessChecks = append(livenessChecks, cached)
+		}
+	}
+
+	s.Get(HealthPath, func(c *Context) {
+		writeHealthResponse(c, runHealthChecks(c.Request.Context(), livenessChecks))
+	})
+
+	s.Get(ReadinessPath, func(c *Context) {
+		if s.shuttingDown.Load() {
+			writeHealthResponse(c, HealthResponse{Status: healthStatusShuttingDown})
+			return
+		}
+		writeHealthResponse(c, runHealthChecks(c.Request.Context(), readinessChecks))
+	})
+}
+
+func writeHealthResponse(c *Context, response HealthResponse) {
+	status := http.StatusOK
+	if response.Status != healthStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.Writer.Header().Set("Cache-Control", "no-store")
+	c.Status(status).JSONResponse(response)
+}
+
+func runHealthChecks(ctx context.Context, checks []*cachedHealthCheck) HealthResponse {
+	response := HealthResponse{Status: healthStatusOK}
+	if len(checks) == 0 {
+		return response
+	}
+
+	results := make([]HealthCheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check *cachedHealthCheck) {
+			defer wg.Done()
+			results[i] = check.run(ctx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	response.Checks = make(map[string]HealthCheckResult, len(checks))
+	for i, check := range checks {
+		response.Checks[check.Name] = results[i]
+		if results[i].Status != healthStatusOK {
+			response.Status = healthStatusFailed
+		}
+	}
+	return response
+}
+
+// cachedHealthCheck runs the check at most once in CacheTTL
+type cachedHealthCheck struct {
+	HealthCheck
+
+	mux       sync.Mutex
+	result    HealthCheckResult
+	checkedAt time.Time
+}
+
+func (hc *cachedHealthCheck) run(ctx context.Context) HealthCheckResult {
+	hc.mux.Lock()
+	defer hc.mux.Unlock()
+
+	if hc.CacheTTL > 0 && !hc.checkedAt.IsZero() && time.Since(hc.checkedAt) < hc.CacheTTL {
+		return hc.result
+	}
+
+	startTime := time.Now()
+	err := hc.check(ctx)
+
+	result := HealthCheckResult{
+		Status:   healthStatusOK,
+		Duration: time.Since(startTime).String(),
+	}
+	if err != nil {
+		result.Status = healthStatusFailed
+		result.Error = err.Error()
+	}
+
+	// the check was interrupted by the caller, eg: the probe disconnected, it says nothing about the dependency
+	if ctx.Err() != nil {
+		return result
+	}
+
+	hc.result = result
+	hc.checkedAt = time.Now()
+	return result
+}
+
+// check runs the check with the timeout, checks which ignore the context are abandoned on timeout
+func (hc *cachedHealthCheck) check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, hc.Timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if value := recover(); value != nil {
+				done <- &PanicError{Value: value}
+			}
+		}()
+		done <- hc.Check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return ErrHealthCheckTimeout
+		}
+		return ctx.Err()
+	}
+}
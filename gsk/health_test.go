@@ -0,0 +1,133 @@
+package gsk_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthChecks(t *testing.T) {
+	t.Run("reports ok when all checks pass", func(t *testing.T) {
+		s := gsk.New()
+		s.HealthChecks(
+			gsk.HealthCheck{Name: "db", Check: func(ctx context.Context) error { return nil }},
+			gsk.HealthCheck{Name: "cache", Check: func(ctx context.Context) error { return nil }, Liveness: true},
+		)
+
+		rr, _ := s.Test("GET", "/readyz", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+		assert.Regexp(t, `^\{"status":"ok","checks":\{"cache":\{"status":"ok","duration":"[^"]+"\},"db":\{"status":"ok","duration":"[^"]+"\}\}\}$`, rr.Body.String())
+
+		rr, _ = s.Test("GET", "/healthz", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"cache"`)
+		assert.NotContains(t, rr.Body.String(), `"db"`)
+	})
+
+	t.Run("reports failed checks with 503", func(t *testing.T) {
+		s := gsk.New()
+		s.HealthChecks(
+			gsk.HealthCheck{Name: "db", Check: func(ctx context.Context) error { return errors.New("connection refused") }},
+			gsk.HealthCheck{Name: "slow", Timeout: 10 * time.Millisecond, Check: func(ctx context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			}},
+			gsk.HealthCheck{Name: "broken", Check: func(ctx context.Context) error { panic("boom") }},
+		)
+
+		rr, _ := s.Test("GET", "/readyz", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"status":"failed"`)
+		assert.Contains(t, rr.Body.String(), `"db":{"status":"failed","error":"connection refused"`)
+		assert.Contains(t, rr.Body.String(), `"slow":{"status":"failed","error":"health check timed out"`)
+		assert.Contains(t, rr.Body.String(), `"broken":{"status":"failed","error":"panic: boom"`)
+
+		rr, _ = s.Test("GET", "/healthz", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"status": "ok"}`, rr.Body.String())
+	})
+
+	t.Run("caches the results", func(t *testing.T) {
+		var calls int32
+		s := gsk.New()
+		s.HealthChecks(
+			gsk.HealthCheck{Name: "cached", CacheTTL: time.Minute, Check: func(ctx context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				return nil
+			}},
+		)
+
+		s.Test("GET", "/readyz", nil)
+		s.Test("GET", "/readyz", nil)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("does not cache the results of cancelled requests", func(t *testing.T) {
+		s := gsk.New()
+		s.HealthChecks(gsk.HealthCheck{Name: "db", CacheTTL: time.Minute, Check: func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(20 * time.Millisecond):
+				return nil
+			}
+		}})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, "GET", "/readyz", nil)
+		rr := httptest.NewRecorder()
+		s.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+		rr2, _ := s.Test("GET", "/readyz", nil)
+		assert.Equal(t, http.StatusOK, rr2.Code)
+	})
+
+	t.Run("readiness fails on shutdown", func(t *testing.T) {
+		s := gsk.New()
+		s.HealthChecks()
+
+		rr, _ := s.Test("GET", "/readyz", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		assert.NoError(t, s.Shutdown())
+
+		rr, _ = s.Test("GET", "/readyz", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		assert.JSONEq(t, `{"status": "shutting_down"}`, rr.Body.String())
+
+		rr, _ = s.Test("GET", "/healthz", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("readiness fails during the shutdown drain delay", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{ShutdownDrainDelay: 100 * time.Millisecond})
+		s.HealthChecks()
+
+		done := make(chan error)
+		go func() {
+			done <- s.Shutdown()
+		}()
+
+		assert.Eventually(t, func() bool {
+			rr, _ := s.Test("GET", "/readyz", nil)
+			return rr.Code == http.StatusServiceUnavailable
+		}, 50*time.Millisecond, time.Millisecond)
+
+		select {
+		case <-done:
+			t.Fatal("shutdown returned before the drain delay")
+		default:
+		}
+		assert.NoError(t, <-done)
+	})
+}
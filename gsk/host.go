@@ -0,0 +1,119 @@
+package gsk
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type hostParamsKey struct{}
+
+// hostRouter routes the requests of the hosts matching the pattern
+type hostRouter struct {
+	pattern string
+	labels  []string
+	router  Router
+}
+
+// Host returns a route group for the requests to the hosts matching the pattern
+// labels starting with : capture the label as a param, available with c.Param, and * matches any label
+// hosts are matched in the order they are added, requests to other hosts use the server routes,
+// and paths not registered for the host fall back to the server routes
+// usage example:
+// admin := server.Host("admin.example.com")
+// tenants := server.Host(":tenant.example.com")
+// tenants.Get("/users", func(c *gsk.Context) { c.Param("tenant") })
+func (s *Server) Host(pattern string) *RouteGroup {
+	labels := strings.Split(strings.TrimSuffix(pattern, "."), ".")
+	for i, label := range labels {
+		if !strings.HasPrefix(label, ":") {
+			labels[i] = strings.ToLower(label)
+		}
+	}
+	pattern = strings.Join(labels, ".")
+
+	for _, host := range s.hosts {
+		if host.pattern == pattern {
+			return &RouteGroup{server: s, host: host}
+		}
+	}
+
+	router := s.config.Router()
+	router.NotFound(s.router.ServeHTTP)
+	router.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		s.methodNotAllowed(w, r)
+	})
+	router.GlobalOPTIONS(func(w http.ResponseWriter, r *http.Request) {
+		s.globalOptions(w, r)
+	})
+
+	host := &hostRouter{
+		pattern: pattern,
+		labels:  labels,
+		router:  router,
+	}
+	s.hosts = append(s.hosts, host)
+
+	return &RouteGroup{server: s, host: host}
+}
+
+// routerFor returns the router of the host of the group, or the server router
+func (s *Server) routerFor(group *RouteGroup) Router {
+	if group != nil && group.host != nil {
+		return group.host.router
+	}
+	return s.router
+}
+
+// match returns the captured params if the host matches the pattern
+func (h *hostRouter) match(host string) (map[string]string, bool) {
+	labels := strings.Split(host, ".")
+	if len(labels) != len(h.labels) {
+		return nil, false
+	}
+
+	var params map[string]string
+	for i, label := range h.labels {
+		switch {
+		case label == "*":
+		case strings.HasPrefix(label, ":"):
+			if params == nil {
+				params = map[string]string{}
+			}
+			params[label[1:]] = labels[i]
+		case label != labels[i]:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// serveHost serves the request with the router of the first host matching the request host
+func (s *Server) serveHost(w http.ResponseWriter, r *http.Request) bool {
+	if len(s.hosts) == 0 {
+		return false
+	}
+
+	requestHost := normalizeHost(r.Host)
+	for _, host := range s.hosts {
+		params, ok := host.match(requestHost)
+		if !ok {
+			continue
+		}
+		if params != nil {
+			r = r.WithContext(context.WithValue(r.Context(), hostParamsKey{}, params))
+		}
+		host.router.ServeHTTP(w, r)
+		return true
+	}
+	return false
+}
+
+// normalizeHost lowercases the host and removes the port and the trailing dot
+func normalizeHost(host string) string {
+	host = strings.ToLower(host)
+	if i := strings.LastIndex(host, ":"); i > strings.LastIndex(host, "]") {
+		host = host[:i]
+	}
+	return strings.TrimSuffix(host, ".")
+}
@@ -0,0 +1,144 @@
+package gsk_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Host(t *testing.T) {
+	serve := func(s *gsk.Server, method string, host string, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Host = host
+		rr := httptest.NewRecorder()
+		s.ServeHTTP(rr, req)
+		return rr
+	}
+
+	respond := func(body string) gsk.HandlerFunc {
+		return func(c *gsk.Context) {
+			c.Status(http.StatusOK).StringResponse(body)
+		}
+	}
+
+	newServer := func() *gsk.Server {
+		s := gsk.New()
+		s.Get("/", respond("main"))
+		s.Get("/healthz", respond("healthy"))
+
+		admin := s.Host("Admin.Example.com")
+		admin.Get("/", respond("admin"))
+		admin.RouteGroup("/users").Get("/:id", func(c *gsk.Context) {
+			c.Status(http.StatusOK).StringResponse("admin user " + c.Param("id"))
+		})
+
+		tenants := s.Host(":tenant.example.com")
+		tenants.Get("/", func(c *gsk.Context) {
+			c.Status(http.StatusOK).StringResponse("tenant " + c.Param("tenant"))
+		})
+		tenants.Post("/items", respond("created"))
+		return s
+	}
+
+	t.Run("routes requests by host", func(t *testing.T) {
+		s := newServer()
+
+		testCases := []struct {
+			host     string
+			path     string
+			expected string
+		}{
+			{"example.com", "/", "main"},
+			{"admin.example.com", "/", "admin"},
+			{"ADMIN.example.com:8080", "/", "admin"},
+			{"admin.example.com", "/users/12", "admin user 12"},
+			{"acme.example.com", "/", "tenant acme"},
+			{"acme.example.com.", "/", "tenant acme"},
+			{"a.b.example.com", "/", "main"},
+		}
+
+		for _, tc := range testCases {
+			rr := serve(s, "GET", tc.host, tc.path)
+			assert.Equal(t, http.StatusOK, rr.Code, tc.host+tc.path)
+			assert.Equal(t, tc.expected, rr.Body.String(), tc.host+tc.path)
+		}
+	})
+
+	t.Run("falls back to the server routes", func(t *testing.T) {
+		s := newServer()
+
+		rr := serve(s, "GET", "admin.example.com", "/healthz")
+		assert.Equal(t, "healthy", rr.Body.String())
+
+		rr = serve(s, "GET", "admin.example.com", "/missing")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		rr = serve(s, "GET", "example.com", "/users/12")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("responds with method not allowed for the host routes", func(t *testing.T) {
+		s := newServer()
+
+		rr := serve(s, "DELETE", "acme.example.com", "/items")
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+		assert.Equal(t, "OPTIONS, POST", rr.Header().Get("Allow"))
+	})
+
+	t.Run("applies the server middlewares and records the host", func(t *testing.T) {
+		s := newServer()
+		s.Use(func(next gsk.HandlerFunc) gsk.HandlerFunc {
+			return func(c *gsk.Context) {
+				c.SetHeader("X-Middleware", "applied")
+				next(c)
+			}
+		})
+
+		rr := serve(s, "GET", "admin.example.com", "/")
+		assert.Equal(t, "applied", rr.Header().Get("X-Middleware"))
+
+		routes := s.Routes()
+		assert.Equal(t, "", routes[0].Host)
+		assert.Equal(t, "admin.example.com", routes[2].Host)
+		assert.Equal(t, ":tenant.example.com", routes[4].Host)
+	})
+}
+
+func TestVersioned(t *testing.T) {
+	s := gsk.New()
+	s.Get("/users", gsk.Versioned(map[string]gsk.HandlerFunc{
+		"v1": func(c *gsk.Context) {
+			c.Status(http.StatusOK).JSONResponse(gsk.Map{"version": c.APIVersion(), "name": "adharsh"})
+		},
+		"v2": func(c *gsk.Context) {
+			c.Status(http.StatusOK).JSONResponse(gsk.Map{"version": c.APIVersion(), "first_name": "adharsh"})
+		},
+	}, "v1"))
+
+	testCases := []struct {
+		name     string
+		accept   string
+		status   int
+		expected string
+	}{
+		{"default version without accept", "", http.StatusOK, `{"version": "v1", "name": "adharsh"}`},
+		{"default version for json", "application/json", http.StatusOK, `{"version": "v1", "name": "adharsh"}`},
+		{"vendor media type", "application/vnd.app.v2+json", http.StatusOK, `{"version": "v2", "first_name": "adharsh"}`},
+		{"vendor media type among others", "text/html, application/vnd.app.v1+json;q=0.9", http.StatusOK, `{"version": "v1", "name": "adharsh"}`},
+		{"unknown version", "application/vnd.app.v9+json", http.StatusNotAcceptable, `{"error": "not_acceptable"}`},
+		{"vendor media type without a version", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", http.StatusOK, `{"version": "v1", "name": "adharsh"}`},
+		{"skips vendor media types without a version", "application/vnd.ms-excel, application/vnd.app.v2+json", http.StatusOK, `{"version": "v2", "first_name": "adharsh"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr, _ := s.Test("GET", "/users", nil, gsk.TestParams{Headers: map[string]string{"Accept": tc.accept}})
+			assert.Equal(t, tc.status, rr.Code)
+			assert.JSONEq(t, tc.expected, rr.Body.String())
+			assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+		})
+	}
+}
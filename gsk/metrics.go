@@ -0,0 +1,31 @@
+package gsk
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/adharshmk96/stk/pkg/metrics"
+)
+
+// MetricsHandler serves the metrics in the Prometheus text exposition format on the path
+// metrics.DefaultRegistry is served if no registry is given, it is used by middleware.Metrics
+// usage example:
+// server.Use(middleware.Metrics())
+// server.MetricsHandler("/metrics")
+func (s *Server) MetricsHandler(path string, registry ...*metrics.Registry) {
+	metricsRegistry := metrics.DefaultRegistry
+	if len(registry) > 0 && registry[0] != nil {
+		metricsRegistry = registry[0]
+	}
+
+	s.Get(path, func(c *Context) {
+		var buf bytes.Buffer
+		if _, err := metricsRegistry.WriteTo(&buf); err != nil {
+			c.ErrorResponse(err)
+			return
+		}
+
+		c.Writer.Header().Set("Content-Type", metrics.ContentType)
+		c.Status(http.StatusOK).RawResponse(buf.Bytes())
+	})
+}
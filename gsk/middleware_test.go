@@ -0,0 +1,129 @@
+package gsk_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+// tracingMiddleware appends the name to the X-Order header when the request passes through it
+func tracingMiddleware(name string) gsk.Middleware {
+	return func(next gsk.HandlerFunc) gsk.HandlerFunc {
+		return func(c *gsk.Context) {
+			c.Writer.Header().Add("X-Order", name)
+			next(c)
+		}
+	}
+}
+
+func order(rr interface{ Header() http.Header }) string {
+	return strings.Join(rr.Header().Values("X-Order"), ",")
+}
+
+func TestMiddlewareOrdering(t *testing.T) {
+	handler := func(c *gsk.Context) {
+		c.Status(http.StatusOK).StringResponse("ok")
+	}
+
+	t.Run("server middlewares apply to routes registered before and after Use", func(t *testing.T) {
+		s := gsk.New()
+		s.Get("/before", handler)
+		s.Use(tracingMiddleware("first"), tracingMiddleware("second"))
+		s.Get("/after", handler)
+		s.Use(tracingMiddleware("third"))
+
+		rg := s.RouteGroup("/api")
+		rg.Get("/group", handler)
+
+		for _, path := range []string{"/before", "/after", "/api/group"} {
+			rr, _ := s.Test("GET", path, nil)
+			assert.Equal(t, http.StatusOK, rr.Code, path)
+			assert.Equal(t, "first,second,third", order(&rr), path)
+		}
+	})
+
+	t.Run("route middlewares run after the server middlewares", func(t *testing.T) {
+		s := gsk.New()
+		s.Use(tracingMiddleware("server"))
+		s.Get("/users", handler, tracingMiddleware("route1"), tracingMiddleware("route2"))
+		s.Get("/public", handler)
+
+		rr, _ := s.Test("GET", "/users", nil)
+		assert.Equal(t, "server,route1,route2", order(&rr))
+
+		rr, _ = s.Test("GET", "/public", nil)
+		assert.Equal(t, "server", order(&rr))
+	})
+
+	t.Run("group middlewares apply to routes registered before Use", func(t *testing.T) {
+		s := gsk.New()
+		s.Use(tracingMiddleware("server"))
+
+		rg := s.RouteGroup("/api")
+		rg.Get("/users", handler, tracingMiddleware("route"))
+		rg.Use(tracingMiddleware("group"))
+
+		rr, _ := s.Test("GET", "/api/users", nil)
+		assert.Equal(t, "server,group,route", order(&rr))
+	})
+
+	t.Run("nested groups run the parent middlewares first", func(t *testing.T) {
+		s := gsk.New()
+
+		api := s.RouteGroup("/api")
+		admin := api.RouteGroup("/admin")
+		admin.Use(tracingMiddleware("admin"))
+		admin.Get("/users", handler)
+		api.Use(tracingMiddleware("api"))
+		api.Get("/users", handler)
+
+		rr, _ := s.Test("GET", "/api/admin/users", nil)
+		assert.Equal(t, "api,admin", order(&rr))
+
+		rr, _ = s.Test("GET", "/api/users", nil)
+		assert.Equal(t, "api", order(&rr))
+	})
+
+	t.Run("sibling groups do not share middlewares", func(t *testing.T) {
+		s := gsk.New()
+
+		api := s.RouteGroup("/api")
+		api.Use(tracingMiddleware("api"))
+		users := api.RouteGroup("/users")
+		orders := api.RouteGroup("/orders")
+		users.Use(tracingMiddleware("users"))
+		orders.Use(tracingMiddleware("orders"))
+		users.Get("/", handler)
+		orders.Get("/", handler)
+
+		rr, _ := s.Test("GET", "/api/users", nil)
+		assert.Equal(t, "api,users", order(&rr))
+
+		rr, _ = s.Test("GET", "/api/orders", nil)
+		assert.Equal(t, "api,orders", order(&rr))
+	})
+
+	t.Run("route table lists the middlewares in the order they run", func(t *testing.T) {
+		s := gsk.New()
+		rg := s.RouteGroup("/api")
+		rg.Get("/users", handler, authMiddleware)
+		rg.Use(loggerMiddleware)
+		s.Use(loggerMiddleware)
+
+		routes := s.Routes()
+		assert.Equal(t, []string{"gsk_test.loggerMiddleware", "gsk_test.loggerMiddleware", "gsk_test.authMiddleware"}, routes[0].Middlewares)
+	})
+
+	t.Run("preflight requests pass through the server middlewares once", func(t *testing.T) {
+		s := gsk.New()
+		s.Use(tracingMiddleware("server"))
+		s.Get("/users", handler)
+
+		rr, _ := s.Test("OPTIONS", "/users", nil)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, "server", order(&rr))
+	})
+}
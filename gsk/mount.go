@@ -0,0 +1,225 @@
+package gsk
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// mountMethods are the methods registered for a mounted handler
+var mountMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodConnect,
+	http.MethodTrace,
+}
+
+const mountPathParam = "mountpath"
+
+// ServeHTTP makes the server a http.Handler, so it can be mounted under another server
+// or served by any net/http server
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.serveHost(w, r) {
+		return
+	}
+	s.router.ServeHTTP(w, r)
+}
+
+// Mount serves the http.Handler for all the methods and paths under the prefix
+// the prefix is removed from the request path, so /admin/users is served as /users by the mounted handler
+// the handler runs through the server middlewares, its response is buffered like a gsk response
+// the prefix can not be the root path, use server.NotFound(gsk.WrapHandler(handler)) to serve the unmatched requests
+// usage example:
+// server.Mount("/admin", adminServer)
+// server.Mount("/legacy", legacyMux)
+func (s *Server) Mount(prefix string, handler http.Handler) {
+	s.mount(prefix, handler, nil)
+}
+
+// Mount serves the http.Handler under the prefix of the group, with the group middlewares, see Server.Mount
+func (rg *RouteGroup) Mount(prefix string, handler http.Handler) {
+	rg.server.mount(rg.pathPrefix+prefix, handler, rg)
+}
+
+func (s *Server) mount(prefix string, handler http.Handler, group *RouteGroup) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" {
+		// the catch-all path at the root conflicts with every other route of the router
+		panic("gsk: cannot mount a handler at the root path, use server.NotFound(gsk.WrapHandler(handler)) to serve the unmatched requests")
+	}
+	catchAllPath := prefix + "/*" + mountPathParam
+
+	mountHandler := WrapHandler(handler)
+	mounted := func(c *Context) {
+		c.Request = stripPrefix(c.Request, c.Param(mountPathParam))
+		mountHandler(c)
+	}
+
+	route := &Route{
+		Method:  "*",
+		Path:    catchAllPath,
+		Handler: fmt.Sprintf("%T", handler),
+		group:   group,
+		hidden:  true,
+	}
+	if group != nil {
+		route.Group = group.pathPrefix
+		if group.host != nil {
+			route.Host = group.host.pattern
+		}
+		groupHandler := mounted
+		mounted = func(c *Context) {
+			applyMiddlewares(group.chain(), groupHandler)(c)
+		}
+	}
+	s.routes = append(s.routes, route)
+
+	router := s.routerFor(group)
+	for _, method := range mountMethods {
+		router.HandlerFunc(method, prefix, wrapHandlerFunc(s, catchAllPath, mounted))
+		router.HandlerFunc(method, catchAllPath, wrapHandlerFunc(s, catchAllPath, mounted))
+	}
+}
+
+// stripPrefix returns a shallow copy of the request with the path replaced by the mounted path
+func stripPrefix(r *http.Request, path string) *http.Request {
+	if path == "" {
+		path = "/"
+	}
+
+	stripped := new(http.Request)
+	*stripped = *r
+	stripped.URL = new(url.URL)
+	*stripped.URL = *r.URL
+	stripped.URL.Path = path
+	stripped.URL.RawPath = ""
+	return stripped
+}
+
+// WrapHandler converts a http.Handler into a gsk HandlerFunc
+// the status and body written by the handler are buffered into the gsk response,
+// so gsk middlewares can read and change them. Handlers can stream the response with http.Flusher,
+// eg: server sent events, or take over the connection with http.Hijacker, eg: websockets,
+// the response is written directly after that and middlewares can no longer change it
+// usage example:
+// server.Get("/debug/pprof/*name", gsk.WrapHandler(http.HandlerFunc(pprof.Index)))
+func WrapHandler(handler http.Handler) HandlerFunc {
+	return func(c *Context) {
+		handler.ServeHTTP(&bufferedWriter{c: c, header: c.Writer.Header()}, c.Request)
+	}
+}
+
+// WrapMiddleware converts a net/http middleware into a gsk Middleware
+// the gsk response of the next handlers is written to the writer of the middleware,
+// so the middleware can observe and change it, the result is buffered into the gsk response
+// usage example:
+// server.Use(gsk.WrapMiddleware(handlers.ProxyHeaders))
+func WrapMiddleware(middleware func(http.Handler) http.Handler) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			writer := c.Writer
+
+			handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				c.Request = r
+				c.Writer = w
+				next(c)
+				c.Writer = writer
+
+				// responses written directly, eg: Redirect, already went through the middleware writer
+				if c.responseWritten {
+					c.responseWritten = false
+					return
+				}
+
+				// the response is handed to the middleware writer, which writes it back to the buffer
+				status, body := c.responseStatus, c.responseBody
+				c.responseStatus, c.responseBody = 0, nil
+				if status == 0 {
+					status = http.StatusOK
+				}
+				w.WriteHeader(status)
+				w.Write(body)
+			}))
+
+			handler.ServeHTTP(&bufferedWriter{c: c, header: writer.Header()}, c.Request)
+			c.Writer = writer
+		}
+	}
+}
+
+// bufferedWriter is a http.ResponseWriter that writes the status and body into the buffered gsk response
+// until the handler flushes or hijacks it
+type bufferedWriter struct {
+	c           *Context
+	header      http.Header
+	wroteHeader bool
+	// streaming is set once the response is flushed, the writes go directly to the gsk writer
+	streaming bool
+}
+
+func (bw *bufferedWriter) Header() http.Header {
+	return bw.header
+}
+
+func (bw *bufferedWriter) WriteHeader(status int) {
+	if bw.wroteHeader {
+		return
+	}
+	bw.wroteHeader = true
+	bw.c.responseStatus = status
+}
+
+func (bw *bufferedWriter) Write(b []byte) (int, error) {
+	if !bw.wroteHeader {
+		bw.WriteHeader(http.StatusOK)
+	}
+	if bw.streaming {
+		return bw.c.Writer.Write(b)
+	}
+	bw.c.responseBody = append(bw.c.responseBody, b...)
+	return len(b), nil
+}
+
+// Flush writes the buffered response and flushes the gsk writer, the later writes are not buffered
+func (bw *bufferedWriter) Flush() {
+	if !bw.wroteHeader {
+		bw.WriteHeader(http.StatusOK)
+	}
+	if !bw.streaming {
+		bw.streaming = true
+		writeResponseWithStatus(bw.c)
+		bw.c.responseBody = nil
+	}
+	if flusher, ok := bw.c.Writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection, the gsk response is not written after that
+func (bw *bufferedWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := bw.c.Writer.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("gsk: %T does not support hijacking: %w", bw.c.Writer, http.ErrNotSupported)
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	bw.streaming = true
+	bw.c.responseWritten = true
+	bw.c.responseBody = nil
+	return conn, rw, nil
+}
+
+// Unwrap returns the gsk writer, so http.ResponseController can set the deadlines of the connection
+func (bw *bufferedWriter) Unwrap() http.ResponseWriter {
+	return bw.c.Writer
+}
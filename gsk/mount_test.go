@@ -0,0 +1,233 @@
+package gsk_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Mount(t *testing.T) {
+	t.Run("mounts a http.Handler with the prefix removed", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Path", r.URL.Path)
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(r.Method + " " + r.URL.Path + "?" + r.URL.RawQuery))
+		})
+
+		s := gsk.New()
+		s.Use(func(next gsk.HandlerFunc) gsk.HandlerFunc {
+			return func(c *gsk.Context) {
+				next(c)
+				c.SetHeader("X-Status", http.StatusText(c.GetStatusCode()))
+			}
+		})
+		s.Mount("/legacy", mux)
+
+		testCases := []struct {
+			method   string
+			path     string
+			expected string
+		}{
+			{http.MethodGet, "/legacy", "GET /?"},
+			{http.MethodGet, "/legacy/", "GET /?"},
+			{http.MethodPost, "/legacy/users/1?full=true", "POST /users/1?full=true"},
+			{http.MethodDelete, "/legacy/users/1", "DELETE /users/1?"},
+		}
+
+		for _, tc := range testCases {
+			rr, _ := s.Test(tc.method, tc.path, nil)
+			assert.Equal(t, http.StatusAccepted, rr.Code, tc.path)
+			assert.Equal(t, tc.expected, rr.Body.String(), tc.path)
+			assert.Equal(t, "Accepted", rr.Header().Get("X-Status"), tc.path)
+		}
+	})
+
+	t.Run("mounts a gsk server under the prefix", func(t *testing.T) {
+		admin := gsk.New()
+		admin.Use(func(next gsk.HandlerFunc) gsk.HandlerFunc {
+			return func(c *gsk.Context) {
+				c.SetHeader("X-Admin", "true")
+				next(c)
+			}
+		})
+		admin.Get("/users/:id", func(c *gsk.Context) {
+			c.Status(http.StatusOK).JSONResponse(gsk.Map{"id": c.Param("id"), "route": c.Route()})
+		})
+
+		s := gsk.New()
+		s.Get("/users", func(c *gsk.Context) {
+			c.Status(http.StatusOK).StringResponse("main")
+		})
+		s.Mount("/admin", admin)
+
+		rr, _ := s.Test("GET", "/admin/users/12", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"id": "12", "route": "/users/:id"}`, rr.Body.String())
+		assert.Equal(t, "true", rr.Header().Get("X-Admin"))
+
+		rr, _ = s.Test("GET", "/admin/missing", nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, "true", rr.Header().Get("X-Admin"))
+
+		rr, _ = s.Test("GET", "/users", nil)
+		assert.Equal(t, "main", rr.Body.String())
+		assert.Empty(t, rr.Header().Get("X-Admin"))
+	})
+
+	t.Run("mounts under a route group with the group middlewares", func(t *testing.T) {
+		s := gsk.New()
+		rg := s.RouteGroup("/api")
+		rg.Use(func(next gsk.HandlerFunc) gsk.HandlerFunc {
+			return func(c *gsk.Context) {
+				c.SetHeader("X-Group", "api")
+				next(c)
+			}
+		})
+		rg.Mount("/v1", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.URL.Path))
+		}))
+
+		rr, _ := s.Test("GET", "/api/v1/ping", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "/ping", rr.Body.String())
+		assert.Equal(t, "api", rr.Header().Get("X-Group"))
+
+		routes := s.Routes()
+		assert.Equal(t, "*", routes[0].Method)
+		assert.Equal(t, "/api/v1/*mountpath", routes[0].Path)
+		assert.Equal(t, "http.HandlerFunc", routes[0].Handler)
+	})
+
+	t.Run("panics when mounted at the root path", func(t *testing.T) {
+		s := gsk.New()
+		assert.Panics(t, func() {
+			s.Mount("/", http.NotFoundHandler())
+		})
+	})
+}
+
+func TestWrapHandler(t *testing.T) {
+	t.Run("buffers the response of the handler", func(t *testing.T) {
+		s := gsk.New()
+		s.Use(func(next gsk.HandlerFunc) gsk.HandlerFunc {
+			return func(c *gsk.Context) {
+				next(c)
+				c.RawResponse(append(c.GetResponseBody(), []byte(" world")...))
+			}
+		})
+		s.Get("/files/*name", gsk.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusCreated)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("hello"))
+		})))
+
+		rr, _ := s.Test("GET", "/files/a.txt", nil)
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "text/plain", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "hello world", rr.Body.String())
+	})
+
+	t.Run("streams the response when the handler flushes", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		var flushed string
+
+		s := gsk.New()
+		s.Get("/events", gsk.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("data: 1\n\n"))
+			w.(http.Flusher).Flush()
+			flushed = rr.Body.String()
+			w.Write([]byte("data: 2\n\n"))
+		})))
+
+		s.ServeHTTP(rr, httptest.NewRequest("GET", "/events", nil))
+
+		assert.Equal(t, "data: 1\n\n", flushed)
+		assert.True(t, rr.Flushed)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/event-stream", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "data: 1\n\ndata: 2\n\n", rr.Body.String())
+	})
+
+	t.Run("fails to hijack a writer which does not support it", func(t *testing.T) {
+		var hijackErr error
+
+		s := gsk.New()
+		s.Get("/ws", gsk.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _, hijackErr = w.(http.Hijacker).Hijack()
+		})))
+
+		s.Test("GET", "/ws", nil)
+		assert.True(t, errors.Is(hijackErr, http.ErrNotSupported))
+	})
+}
+
+func TestWrapMiddleware(t *testing.T) {
+	// statusRecorder is a net/http middleware which records the status written by the next handler
+	statusRecorder := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorder := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+			w.Header().Set("X-Recorded-Status", http.StatusText(recorder.status))
+		})
+	}
+
+	requireToken := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	newServer := func() *gsk.Server {
+		s := gsk.New()
+		s.Use(gsk.WrapMiddleware(statusRecorder), gsk.WrapMiddleware(requireToken))
+		s.Get("/users", func(c *gsk.Context) {
+			c.Status(http.StatusTeapot).StringResponse("users")
+		})
+		s.Get("/redirect", func(c *gsk.Context) {
+			c.Redirect("/users")
+		})
+		return s
+	}
+
+	t.Run("passes the gsk response through the middleware writer", func(t *testing.T) {
+		rr, _ := newServer().Test("GET", "/users", nil, gsk.TestParams{Headers: map[string]string{"Authorization": "token"}})
+		assert.Equal(t, http.StatusTeapot, rr.Code)
+		assert.Equal(t, "users", rr.Body.String())
+		assert.Equal(t, "I'm a teapot", rr.Header().Get("X-Recorded-Status"))
+	})
+
+	t.Run("keeps responses written by the middleware", func(t *testing.T) {
+		rr, _ := newServer().Test("GET", "/users", nil)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, "unauthorized\n", rr.Body.String())
+		assert.Equal(t, "Unauthorized", rr.Header().Get("X-Recorded-Status"))
+	})
+
+	t.Run("keeps redirects written by the handler", func(t *testing.T) {
+		rr, _ := newServer().Test("GET", "/redirect", nil, gsk.TestParams{Headers: map[string]string{"Authorization": "token"}})
+		assert.Equal(t, http.StatusTemporaryRedirect, rr.Code)
+		assert.Equal(t, "/users", rr.Header().Get("Location"))
+		assert.Equal(t, "Temporary Redirect", rr.Header().Get("X-Recorded-Status"))
+	})
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,581 @@
+package gsk
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	DEFAULT_OPENAPI_PATH = "/openapi.json"
+	openAPIVersion       = "3.0.3"
+)
+
+type OpenAPIConfig struct {
+	Title       string
+	Version     string
+	Description string
+	// Servers are the base urls of the api, eg: https://api.example.com
+	Servers []string
+	// Path of the OpenAPI document, default /openapi.json
+	Path string
+	// SwaggerUIPath serves a Swagger UI page for the document when set, eg: /docs
+	// the page loads the swagger-ui scripts from SwaggerUIAssetsURL
+	SwaggerUIPath string
+	// SwaggerUIAssetsURL is the base url of the swagger-ui-dist files, default DefaultSwaggerUIAssetsURL
+	// serve the files from the app, eg: /static/swagger-ui, to not run scripts from a CDN on the origin of the app
+	SwaggerUIAssetsURL string
+}
+
+// DefaultSwaggerUIAssetsURL is the pinned version of swagger-ui-dist on unpkg used by the Swagger UI page
+const DefaultSwaggerUIAssetsURL = "https://unpkg.com/swagger-ui-dist@5.17.14"
+
+// OpenAPI serves the OpenAPI 3 document of the registered routes
+// the document is generated from the route table on every request,
+// so routes registered after calling OpenAPI are included
+// usage example:
+// server.OpenAPI(gsk.OpenAPIConfig{Title: "Users API", Version: "1.0.0", SwaggerUIPath: "/docs"})
+func (s *Server) OpenAPI(config OpenAPIConfig) {
+	if config.Path == "" {
+		config.Path = DEFAULT_OPENAPI_PATH
+	}
+	if config.Title == "" {
+		config.Title = "API"
+	}
+	if config.Version == "" {
+		config.Version = "1.0.0"
+	}
+	if config.SwaggerUIAssetsURL == "" {
+		config.SwaggerUIAssetsURL = DefaultSwaggerUIAssetsURL
+	}
+
+	s.Get(config.Path, func(c *Context) {
+		document, err := s.OpenAPIDocument(config)
+		if err != nil {
+			c.ErrorResponse(err)
+			return
+		}
+		c.JSONResponse(document)
+	}).hidden = true
+
+	if config.SwaggerUIPath != "" {
+		page := strings.ReplaceAll(swaggerUIPage, "{{title}}", jsString(config.Title))
+		page = strings.ReplaceAll(page, "{{url}}", jsString(config.Path))
+		page = strings.ReplaceAll(page, "{{assets}}", html.EscapeString(strings.TrimSuffix(config.SwaggerUIAssetsURL, "/")))
+
+		s.Get(config.SwaggerUIPath, func(c *Context) {
+			c.Writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+			c.Status(http.StatusOK).RawResponse([]byte(page))
+		}).hidden = true
+	}
+}
+
+// OpenAPIDocument generates the OpenAPI 3 document of the registered routes
+// it fails when routes have the same method and path in the document, eg: routes of different hosts,
+// since the document cannot describe more than one operation for them
+func (s *Server) OpenAPIDocument(config OpenAPIConfig) (Map, error) {
+	generator := &openAPIGenerator{
+		schemas:   Map{},
+		names:     map[reflect.Type]string{},
+		nameTypes: map[string]reflect.Type{},
+	}
+
+	info := Map{"title": config.Title, "version": config.Version}
+	if config.Description != "" {
+		info["description"] = config.Description
+	}
+
+	document := Map{
+		"openapi": openAPIVersion,
+		"info":    info,
+	}
+
+	if len(config.Servers) > 0 {
+		servers := make([]Map, len(config.Servers))
+		for i, url := range config.Servers {
+			servers[i] = Map{"url": url}
+		}
+		document["servers"] = servers
+	}
+
+	paths := Map{}
+	operationRoutes := map[string]*Route{}
+	for _, route := range s.routes {
+		if route.hidden {
+			continue
+		}
+
+		path, pathParams := openAPIPath(route.Path)
+		method := strings.ToLower(route.Method)
+
+		key := route.Method + " " + path
+		if existing, ok := operationRoutes[key]; ok {
+			return nil, fmt.Errorf("openapi: the operation %s is registered more than once, for the hosts %q and %q", key, existing.Host, route.Host)
+		}
+		operationRoutes[key] = route
+
+		item, ok := paths[path].(Map)
+		if !ok {
+			item = Map{}
+			paths[path] = item
+		}
+		item[method] = generator.operation(route, pathParams)
+	}
+	document["paths"] = paths
+
+	if len(generator.schemas) > 0 {
+		document["components"] = Map{"schemas": generator.schemas}
+	}
+
+	return document, nil
+}
+
+// pathParam is a param of the route path with its schema
+type pathParam struct {
+	name   string
+	schema Map
+}
+
+// openAPIPath converts the route path to the OpenAPI format, eg: /users/:id -> /users/{id}
+// constrained params of the radix router are documented with the type or pattern, eg: /users/{id:int}
+func openAPIPath(path string) (string, []pathParam) {
+	var params []pathParam
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		var param pathParam
+		switch {
+		case strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*"):
+			param = pathParam{name: segment[1:], schema: Map{"type": "string"}}
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			name, constraint, _ := strings.Cut(segment[1:len(segment)-1], ":")
+			param = pathParam{name: name, schema: constraintSchema(constraint)}
+		default:
+			continue
+		}
+		params = append(params, param)
+		segments[i] = "{" + param.name + "}"
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+// constraintSchema returns the schema of the param constraint of the radix router
+func constraintSchema(constraint string) Map {
+	switch constraint {
+	case "":
+		return Map{"type": "string"}
+	case "int":
+		return Map{"type": "integer"}
+	case "uint":
+		return Map{"type": "integer", "minimum": 0}
+	case "uuid":
+		return Map{"type": "string", "format": "uuid"}
+	}
+	if expr, ok := paramConstraints[constraint]; ok {
+		constraint = expr
+	}
+	return Map{"type": "string", "pattern": "^(?:" + constraint + ")$"}
+}
+
+type openAPIGenerator struct {
+	// schemas of the named struct types, referenced from the operations
+	schemas Map
+	// names are the component names of the types, nameTypes the types of the names
+	names     map[reflect.Type]string
+	nameTypes map[string]reflect.Type
+}
+
+func (g *openAPIGenerator) operation(route *Route, pathParams []pathParam) Map {
+	doc := route.Doc
+	if doc == nil {
+		doc = &RouteDoc{}
+	}
+
+	operation := Map{}
+	if doc.Summary != "" {
+		operation["summary"] = doc.Summary
+	}
+	if doc.Description != "" {
+		operation["description"] = doc.Description
+	}
+	if len(doc.Tags) > 0 {
+		operation["tags"] = doc.Tags
+	}
+	if doc.OperationID != "" {
+		operation["operationId"] = doc.OperationID
+	}
+	if doc.Deprecated {
+		operation["deprecated"] = true
+	}
+
+	parameters, body := g.request(doc.Request, pathParams)
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	if body != nil {
+		operation["requestBody"] = body
+	}
+
+	operation["responses"] = g.responses(doc.Responses)
+	return operation
+}
+
+// request returns the parameters and the request body from the binding tags of the request struct
+// path parameters of the route which are not in the struct are added with the schema of the route
+func (g *openAPIGenerator) request(request interface{}, pathParams []pathParam) ([]Map, Map) {
+	var parameters []Map
+	documented := map[string]bool{}
+
+	var bodyFields []reflect.StructField
+	var formFields []reflect.StructField
+
+	if request != nil {
+		for _, field := range structFields(reflect.TypeOf(request)) {
+			location, name := bindingLocation(field)
+			switch location {
+			case "path", "query", "header":
+				parameter := Map{
+					"name":     name,
+					"in":       location,
+					"required": location == "path" || isRequired(field),
+					"schema":   g.fieldSchema(field),
+				}
+				parameters = append(parameters, parameter)
+				if location == "path" {
+					documented[name] = true
+				}
+			case "form":
+				formFields = append(formFields, field)
+			default:
+				bodyFields = append(bodyFields, field)
+			}
+		}
+	}
+
+	for _, param := range pathParams {
+		if !documented[param.name] {
+			parameters = append(parameters, Map{
+				"name":     param.name,
+				"in":       "path",
+				"required": true,
+				"schema":   param.schema,
+			})
+		}
+	}
+
+	content := Map{}
+	if len(bodyFields) > 0 {
+		content[MIMEJSON] = Map{"schema": g.objectSchema(bodyFields, jsonFieldName)}
+	}
+	if len(formFields) > 0 {
+		content["application/x-www-form-urlencoded"] = Map{"schema": g.objectSchema(formFields, func(field reflect.StructField) string {
+			_, name := bindingLocation(field)
+			return name
+		})}
+	}
+	if len(content) == 0 {
+		return parameters, nil
+	}
+	return parameters, Map{"required": true, "content": content}
+}
+
+func (g *openAPIGenerator) responses(responses map[int]interface{}) Map {
+	if len(responses) == 0 {
+		return Map{"200": Map{"description": http.StatusText(http.StatusOK)}}
+	}
+
+	statuses := make([]int, 0, len(responses))
+	for status := range responses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+
+	result := Map{}
+	for _, status := range statuses {
+		response := Map{"description": http.StatusText(status)}
+		if body := responses[status]; body != nil {
+			response["content"] = Map{MIMEJSON: Map{"schema": g.schema(reflect.TypeOf(body))}}
+		}
+		result[strconv.Itoa(status)] = response
+	}
+	return result
+}
+
+var validationErrorsType = reflect.TypeOf(ValidationErrors{})
+
+// schema returns the JSON schema of the type, named structs are added to the components
+func (g *openAPIGenerator) schema(t reflect.Type) Map {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return Map{"type": "string", "format": "date-time"}
+	case durationType:
+		return Map{"type": "integer", "format": "int64", "description": "duration in nanoseconds"}
+	case validationErrorsType:
+		return g.validationErrorsSchema()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return Map{"type": "string"}
+	case reflect.Bool:
+		return Map{"type": "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return Map{"type": "integer", "format": "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		// int and uint are 64 bit on the supported platforms
+		return Map{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return Map{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return Map{"type": "number", "format": "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Map{"type": "string", "format": "byte"}
+		}
+		return Map{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return Map{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.objectSchema(structFields(t), jsonFieldName)
+		}
+		name, ok := g.names[t]
+		if !ok {
+			name = g.componentName(t)
+			// registered before generating the fields for recursive types
+			g.schemas[name] = Map{}
+			g.schemas[name] = g.objectSchema(structFields(t), jsonFieldName)
+		}
+		return Map{"$ref": "#/components/schemas/" + name}
+	}
+
+	return Map{}
+}
+
+var (
+	// typeArgPackage matches the package path of the type arguments of generic types, eg: github.com/x/y. in Page[github.com/x/y.User]
+	typeArgPackage = regexp.MustCompile(`[\w.\-]+/`)
+	// invalidComponentChars are not allowed in the component names, ^[a-zA-Z0-9.\-_]+$
+	invalidComponentChars = regexp.MustCompile(`[^a-zA-Z0-9.\-_]+`)
+)
+
+// componentName returns a unique component name for the type, eg: User
+// types with the same name are qualified with the package, eg: billing.User, and numbered when it is the same too
+func (g *openAPIGenerator) componentName(t reflect.Type) string {
+	name := typeArgPackage.ReplaceAllString(t.Name(), "")
+	name = strings.Trim(invalidComponentChars.ReplaceAllString(name, "_"), "_")
+
+	if _, taken := g.nameTypes[name]; taken {
+		pkg := t.PkgPath()
+		if i := strings.LastIndex(pkg, "/"); i >= 0 {
+			pkg = pkg[i+1:]
+		}
+		name = invalidComponentChars.ReplaceAllString(pkg, "_") + "." + name
+	}
+
+	unique := name
+	for i := 2; ; i++ {
+		if _, taken := g.nameTypes[unique]; !taken {
+			break
+		}
+		unique = name + strconv.Itoa(i)
+	}
+
+	g.names[t] = unique
+	g.nameTypes[unique] = t
+	return unique
+}
+
+// objectSchema returns the schema of the struct fields, named by fieldName
+func (g *openAPIGenerator) objectSchema(fields []reflect.StructField, fieldName func(reflect.StructField) string) Map {
+	properties := Map{}
+	var required []string
+
+	for _, field := range fields {
+		name := fieldName(field)
+		if name == "" {
+			continue
+		}
+		properties[name] = g.fieldSchema(field)
+		if isRequired(field) {
+			required = append(required, name)
+		}
+	}
+
+	schema := Map{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// fieldSchema returns the schema of the field with the constraints of the validate tag
+func (g *openAPIGenerator) fieldSchema(field reflect.StructField) Map {
+	schema := g.schema(field.Type)
+	if _, isRef := schema["$ref"]; isRef {
+		return schema
+	}
+
+	if defaultValue, ok := field.Tag.Lookup("default"); ok {
+		schema["default"] = typedDefault(field, defaultValue)
+	}
+
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		rule, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch rule {
+		case "min", "max", "len":
+			limit, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			for _, keyword := range sizeKeywords(schema["type"], rule) {
+				schema[keyword] = limit
+			}
+		case "email":
+			schema["format"] = "email"
+		case "url":
+			schema["format"] = "uri"
+		case "uuid":
+			schema["format"] = "uuid"
+		case "oneof":
+			schema["enum"] = strings.Fields(param)
+		}
+	}
+	return schema
+}
+
+// typedDefault converts the default tag to the type of the field like Bind, eg: "1" -> 1 for an int field
+// the tag is used as it is if it can not be converted
+func typedDefault(field reflect.StructField, raw string) interface{} {
+	value := reflect.New(field.Type).Elem()
+	if err := setFieldValue(value, []string{raw}, field.Tag.Get("time_format")); err != nil {
+		return raw
+	}
+	return value.Interface()
+}
+
+// sizeKeywords returns the JSON schema keywords of the size rule for the schema type
+func sizeKeywords(schemaType interface{}, rule string) []string {
+	keywords := map[string]map[string][]string{
+		"string":  {"min": {"minLength"}, "max": {"maxLength"}, "len": {"minLength", "maxLength"}},
+		"array":   {"min": {"minItems"}, "max": {"maxItems"}, "len": {"minItems", "maxItems"}},
+		"integer": {"min": {"minimum"}, "max": {"maximum"}},
+		"number":  {"min": {"minimum"}, "max": {"maximum"}},
+	}
+	schemaTypeName, _ := schemaType.(string)
+	return keywords[schemaTypeName][rule]
+}
+
+func (g *openAPIGenerator) validationErrorsSchema() Map {
+	name, ok := g.names[validationErrorsType]
+	if !ok {
+		name = g.componentName(validationErrorsType)
+		g.schemas[name] = Map{
+			"type": "object",
+			"properties": Map{
+				"error":  Map{"type": "string", "example": ErrValidation.Error()},
+				"fields": Map{"type": "array", "items": g.schema(reflect.TypeOf(FieldError{}))},
+			},
+		}
+	}
+	return Map{"$ref": "#/components/schemas/" + name}
+}
+
+// structFields returns the exported fields of the struct, including the fields of embedded structs
+func structFields(t reflect.Type) []reflect.StructField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if _, tagged := field.Tag.Lookup("json"); !tagged {
+				fields = append(fields, structFields(field.Type)...)
+				continue
+			}
+		}
+		if field.IsExported() {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// bindingLocation returns the first binding source and name of the field, see Bind
+func bindingLocation(field reflect.StructField) (string, string) {
+	for _, source := range bindSources {
+		name, ok := field.Tag.Lookup(source)
+		if ok && name != "" && name != "-" {
+			return source, name
+		}
+	}
+	return "", ""
+}
+
+// jsonFieldName returns the name of the field in JSON, empty if the field is not encoded
+func jsonFieldName(field reflect.StructField) string {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return field.Name
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func isRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		if strings.TrimSpace(rule) == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// jsString quotes the value as a javascript string for the html page, <, > and & are escaped by json.Marshal
+func jsString(value string) string {
+	quoted, _ := json.Marshal(value)
+	return string(quoted)
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <meta name="viewport" content="width=device-width, initial-scale=1" />
+  <title>Swagger UI</title>
+  <link rel="stylesheet" href="{{assets}}/swagger-ui.css" crossorigin="anonymous" referrerpolicy="no-referrer" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="{{assets}}/swagger-ui-bundle.js" crossorigin="anonymous" referrerpolicy="no-referrer"></script>
+  <script>
+    document.title = {{title}};
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: {{url}}, dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,238 @@
+package gsk_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+type createUserRequest struct {
+	OrgID string   `path:"org_id"`
+	Token string   `header:"X-Token" validate:"required"`
+	Name  string   `json:"name" validate:"required,min=2,max=50"`
+	Email string   `json:"email" validate:"required,email"`
+	Role  string   `json:"role" validate:"oneof=admin member"`
+	Age   int      `json:"age" validate:"min=18"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+type page[T any] struct {
+	Items []T `json:"items"`
+}
+
+type friendResponse = userResponse
+
+type userResponse struct {
+	ID      string         `json:"id"`
+	Name    string         `json:"name"`
+	Friends []userResponse `json:"friends"`
+}
+
+func TestServer_OpenAPI(t *testing.T) {
+	newServer := func() *gsk.Server {
+		s := gsk.New()
+		s.Get("/users/:id", func(c *gsk.Context) {})
+		s.Get("/files/*filepath", func(c *gsk.Context) {})
+		s.Post("/orgs/:org_id/users", func(c *gsk.Context) {}).Document(gsk.RouteDoc{
+			Summary:     "Create a user",
+			Tags:        []string{"users"},
+			OperationID: "createUser",
+			Request:     createUserRequest{},
+			Responses: map[int]interface{}{
+				http.StatusCreated:    userResponse{},
+				http.StatusBadRequest: gsk.ValidationErrors{},
+			},
+		})
+		return s
+	}
+
+	t.Run("serves the document of the registered routes", func(t *testing.T) {
+		s := newServer()
+		s.OpenAPI(gsk.OpenAPIConfig{Title: "Users API", Version: "2.0.0", Servers: []string{"https://api.example.com"}})
+
+		rr, _ := s.Test("GET", "/openapi.json", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var document map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &document))
+
+		assert.Equal(t, "3.0.3", document["openapi"])
+		assert.Equal(t, map[string]interface{}{"title": "Users API", "version": "2.0.0"}, document["info"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"url": "https://api.example.com"}}, document["servers"])
+
+		paths := document["paths"].(map[string]interface{})
+		assert.Len(t, paths, 3)
+		assert.Contains(t, paths, "/users/{id}")
+		assert.Contains(t, paths, "/files/{filepath}")
+		assert.NotContains(t, paths, "/openapi.json")
+	})
+
+	t.Run("documents path parameters and default responses", func(t *testing.T) {
+		document, _ := newServer().OpenAPIDocument(gsk.OpenAPIConfig{})
+		operation := document["paths"].(gsk.Map)["/users/{id}"].(gsk.Map)["get"].(gsk.Map)
+
+		expected := `{
+			"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+			"responses": {"200": {"description": "OK"}}
+		}`
+		assertJSON(t, expected, operation)
+	})
+
+	t.Run("documents the request and responses from the route doc", func(t *testing.T) {
+		document, _ := newServer().OpenAPIDocument(gsk.OpenAPIConfig{})
+		operation := document["paths"].(gsk.Map)["/orgs/{org_id}/users"].(gsk.Map)["post"].(gsk.Map)
+
+		expected := `{
+			"summary": "Create a user",
+			"tags": ["users"],
+			"operationId": "createUser",
+			"parameters": [
+				{"name": "org_id", "in": "path", "required": true, "schema": {"type": "string"}},
+				{"name": "X-Token", "in": "header", "required": true, "schema": {"type": "string"}}
+			],
+			"requestBody": {
+				"required": true,
+				"content": {
+					"application/json": {
+						"schema": {
+							"type": "object",
+							"properties": {
+								"name": {"type": "string", "minLength": 2, "maxLength": 50},
+								"email": {"type": "string", "format": "email"},
+								"role": {"type": "string", "enum": ["admin", "member"]},
+								"age": {"type": "integer", "format": "int64", "minimum": 18},
+								"tags": {"type": "array", "items": {"type": "string"}}
+							},
+							"required": ["name", "email"]
+						}
+					}
+				}
+			},
+			"responses": {
+				"201": {"description": "Created", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/userResponse"}}}},
+				"400": {"description": "Bad Request", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ValidationErrors"}}}}
+			}
+		}`
+		assertJSON(t, expected, operation)
+
+		schemas := document["components"].(gsk.Map)["schemas"]
+		expectedUser := `{
+			"type": "object",
+			"properties": {
+				"id": {"type": "string"},
+				"name": {"type": "string"},
+				"friends": {"type": "array", "items": {"$ref": "#/components/schemas/userResponse"}}
+			}
+		}`
+		assertJSON(t, expectedUser, schemas.(gsk.Map)["userResponse"])
+		assert.Contains(t, schemas, "ValidationErrors")
+	})
+
+	t.Run("documents the default values with the field types", func(t *testing.T) {
+		type listUsersRequest struct {
+			Page   int    `query:"page" default:"1"`
+			Active bool   `query:"active" default:"true"`
+			Sort   string `query:"sort" default:"name"`
+			Limit  uint   `query:"limit" default:"many"`
+		}
+
+		s := gsk.New()
+		s.Get("/users", func(c *gsk.Context) {}).Document(gsk.RouteDoc{Request: listUsersRequest{}})
+
+		document, _ := s.OpenAPIDocument(gsk.OpenAPIConfig{})
+		operation := document["paths"].(gsk.Map)["/users"].(gsk.Map)["get"].(gsk.Map)
+
+		expected := `[
+			{"name": "page", "in": "query", "required": false, "schema": {"type": "integer", "format": "int64", "default": 1}},
+			{"name": "active", "in": "query", "required": false, "schema": {"type": "boolean", "default": true}},
+			{"name": "sort", "in": "query", "required": false, "schema": {"type": "string", "default": "name"}},
+			{"name": "limit", "in": "query", "required": false, "schema": {"type": "integer", "format": "int64", "default": "many"}}
+		]`
+		assertJSON(t, expected, operation["parameters"])
+	})
+
+	t.Run("includes routes registered after OpenAPI", func(t *testing.T) {
+		s := gsk.New()
+		s.OpenAPI(gsk.OpenAPIConfig{Path: "/spec.json"})
+		s.Delete("/users/:id", func(c *gsk.Context) {})
+
+		rr, _ := s.Test("GET", "/spec.json", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"/users/{id}":{"delete"`)
+	})
+
+	t.Run("names the schemas of types with the same name uniquely", func(t *testing.T) {
+		// same name as the userResponse of the package
+		type userResponse struct {
+			Email string `json:"email"`
+		}
+
+		s := gsk.New()
+		s.Get("/users", func(c *gsk.Context) {}).Document(gsk.RouteDoc{
+			Responses: map[int]interface{}{http.StatusOK: page[userResponse]{}},
+		})
+		s.Get("/friends", func(c *gsk.Context) {}).Document(gsk.RouteDoc{
+			Responses: map[int]interface{}{http.StatusOK: page[friendResponse]{}},
+		})
+
+		document, err := s.OpenAPIDocument(gsk.OpenAPIConfig{})
+		assert.NoError(t, err)
+
+		schemas := document["components"].(gsk.Map)["schemas"].(gsk.Map)
+		assert.Contains(t, schemas, "page_gsk_test.userResponse")
+		assert.Contains(t, schemas, "userResponse")
+		assert.Contains(t, schemas, "gsk_test.userResponse")
+		assert.Len(t, schemas, 4)
+		for name := range schemas {
+			assert.Regexp(t, `^[a-zA-Z0-9.\-_]+$`, name)
+		}
+	})
+
+	t.Run("fails for operations registered for more than one host", func(t *testing.T) {
+		s := gsk.New()
+		s.Host("admin.example.com").Get("/users", func(c *gsk.Context) {})
+		s.Host("api.example.com").Get("/users", func(c *gsk.Context) {})
+		s.OpenAPI(gsk.OpenAPIConfig{})
+
+		_, err := s.OpenAPIDocument(gsk.OpenAPIConfig{})
+		assert.EqualError(t, err, `openapi: the operation GET /users is registered more than once, for the hosts "admin.example.com" and "api.example.com"`)
+
+		rr, _ := s.Test("GET", "/openapi.json", nil)
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+
+	t.Run("serves the swagger ui page", func(t *testing.T) {
+		s := gsk.New()
+		s.OpenAPI(gsk.OpenAPIConfig{Title: "Users </script>", SwaggerUIPath: "/docs"})
+
+		rr, _ := s.Test("GET", "/docs", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/html; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Body.String(), `url: "/openapi.json"`)
+		assert.Contains(t, rr.Body.String(), `document.title = "Users \u003c/script\u003e"`)
+		assert.Contains(t, rr.Body.String(), `src="`+gsk.DefaultSwaggerUIAssetsURL+`/swagger-ui-bundle.js"`)
+
+		rr, _ = s.Test("GET", "/openapi.json", nil)
+		assert.NotContains(t, rr.Body.String(), `"/docs"`)
+	})
+
+	t.Run("loads the swagger ui assets from the configured url", func(t *testing.T) {
+		s := gsk.New()
+		s.OpenAPI(gsk.OpenAPIConfig{SwaggerUIPath: "/docs", SwaggerUIAssetsURL: "/static/swagger-ui/"})
+
+		rr, _ := s.Test("GET", "/docs", nil)
+		assert.Contains(t, rr.Body.String(), `href="/static/swagger-ui/swagger-ui.css"`)
+		assert.Contains(t, rr.Body.String(), `src="/static/swagger-ui/swagger-ui-bundle.js"`)
+		assert.NotContains(t, rr.Body.String(), "unpkg.com")
+	})
+}
+
+func assertJSON(t *testing.T, expected string, actual interface{}) {
+	t.Helper()
+	encoded, err := json.Marshal(actual)
+	assert.NoError(t, err)
+	assert.JSONEq(t, expected, string(encoded))
+}
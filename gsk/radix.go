@@ -0,0 +1,388 @@
+package gsk
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// TrailingSlashPolicy decides how the radix router handles a path which matches a route
+// only when the trailing slash is added or removed
+type TrailingSlashPolicy int
+
+const (
+	// TrailingSlashRedirect redirects to the registered path, 301 for GET and 307 for other methods like httprouter
+	TrailingSlashRedirect TrailingSlashPolicy = iota
+	// TrailingSlashIgnore serves the route for both the paths
+	TrailingSlashIgnore
+	// TrailingSlashStrict responds with not found
+	TrailingSlashStrict
+)
+
+type RadixRouterConfig struct {
+	// TrailingSlash is the policy for paths differing from a route only by the trailing slash, default redirect
+	TrailingSlash TrailingSlashPolicy
+}
+
+// paramConstraints are the named constraints of the path params, eg: /users/{id:int}
+// other constraints are used as regular expressions matching the whole segment, eg: /posts/{slug:[a-z0-9-]+}
+var paramConstraints = map[string]string{
+	"int":   `-?[0-9]+`,
+	"uint":  `[0-9]+`,
+	"alpha": `[a-zA-Z]+`,
+	"alnum": `[a-zA-Z0-9]+`,
+	"uuid":  `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+}
+
+// RadixRouter creates a radix tree router with the default configurations
+// usage example:
+// server := gsk.New(&gsk.ServerConfig{Router: gsk.RadixRouter})
+func RadixRouter() Router {
+	return NewRadixRouter(RadixRouterConfig{})
+}
+
+// NewRadixRouter creates a router matching the path segments in a radix tree
+// compared to httprouter, it allows static and param segments at the same position,
+// static segments take priority over params and params over catch-all segments
+// params are defined as :name or {name}, and can be constrained with {name:int}, {name:uuid} or a regular expression,
+// constraints match a single path segment
+func NewRadixRouter(config RadixRouterConfig) Router {
+	return &radixRouter{
+		config: config,
+		root:   &radixNode{},
+	}
+}
+
+type radixParamsKey struct{}
+
+type radixParam struct {
+	key   string
+	value string
+}
+
+// radixParams are the params of the matched route
+type radixParams []radixParam
+
+func (ps radixParams) ByName(name string) string {
+	for _, p := range ps {
+		if p.key == name {
+			return p.value
+		}
+	}
+	return ""
+}
+
+type radixRouter struct {
+	config RadixRouterConfig
+	root   *radixNode
+
+	notFound         http.HandlerFunc
+	methodNotAllowed http.HandlerFunc
+	globalOPTIONS    http.HandlerFunc
+}
+
+// radixNode is a path segment of the registered routes
+type radixNode struct {
+	// static children by segment, matched first
+	static map[string]*radixNode
+	// param children, constrained params are matched before the unconstrained ones
+	params []*radixNode
+	// catch-all child, matched last
+	catchAll *radixNode
+
+	// param name and constraint of param and catch-all nodes
+	paramName  string
+	constraint *regexp.Regexp
+
+	// handlers by method of the route ending at the node
+	handlers map[string]http.HandlerFunc
+}
+
+func (rr *radixRouter) HandlerFunc(method string, path string, handler http.HandlerFunc) {
+	if !strings.HasPrefix(path, "/") {
+		panic("path must begin with '/' in path '" + path + "'")
+	}
+
+	node := rr.root
+	segments := splitPath(path)
+	for i, segment := range segments {
+		node = node.child(segment, i == len(segments)-1, path)
+	}
+
+	if node.handlers == nil {
+		node.handlers = map[string]http.HandlerFunc{}
+	}
+	if _, exists := node.handlers[method]; exists {
+		panic("a handle is already registered for path '" + path + "'")
+	}
+	node.handlers[method] = handler
+}
+
+// child returns the child node for the segment of the path, creating it if needed
+func (n *radixNode) child(segment string, last bool, path string) *radixNode {
+	name, constraint, kind := parseSegment(segment, path)
+
+	switch kind {
+	case segmentCatchAll:
+		if !last {
+			panic("catch-all routes are only allowed at the end of the path in path '" + path + "'")
+		}
+		if n.catchAll == nil {
+			n.catchAll = &radixNode{paramName: name}
+		} else if n.catchAll.paramName != name {
+			panic("catch-all '*" + name + "' in path '" + path + "' conflicts with '*" + n.catchAll.paramName + "'")
+		}
+		return n.catchAll
+
+	case segmentParam:
+		for _, param := range n.params {
+			if constraintString(param.constraint) != constraintString(constraint) {
+				continue
+			}
+			if param.paramName != name {
+				panic("param '" + name + "' in path '" + path + "' conflicts with '" + param.paramName + "'")
+			}
+			return param
+		}
+
+		param := &radixNode{paramName: name, constraint: constraint}
+		n.params = append(n.params, param)
+		// constrained params are matched first, in the order of registration
+		sort.SliceStable(n.params, func(i, j int) bool {
+			return n.params[i].constraint != nil && n.params[j].constraint == nil
+		})
+		return param
+	}
+
+	if n.static == nil {
+		n.static = map[string]*radixNode{}
+	}
+	child, ok := n.static[segment]
+	if !ok {
+		child = &radixNode{}
+		n.static[segment] = child
+	}
+	return child
+}
+
+const (
+	segmentStatic = iota
+	segmentParam
+	segmentCatchAll
+)
+
+// parseSegment returns the param name, the constraint and the kind of the segment
+func parseSegment(segment string, path string) (string, *regexp.Regexp, int) {
+	switch {
+	case strings.HasPrefix(segment, "*"):
+		return requireParamName(segment[1:], path), nil, segmentCatchAll
+	case strings.HasPrefix(segment, ":"):
+		return requireParamName(segment[1:], path), nil, segmentParam
+	case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+		name, constraint, hasConstraint := strings.Cut(segment[1:len(segment)-1], ":")
+		name = requireParamName(name, path)
+		if !hasConstraint {
+			return name, nil, segmentParam
+		}
+		if expr, ok := paramConstraints[constraint]; ok {
+			constraint = expr
+		}
+		re, err := regexp.Compile("^(?:" + constraint + ")$")
+		if err != nil {
+			panic("invalid constraint for param '" + name + "' in path '" + path + "': " + err.Error())
+		}
+		return name, re, segmentParam
+	}
+	return segment, nil, segmentStatic
+}
+
+func requireParamName(name string, path string) string {
+	if name == "" {
+		panic("params must have a name in path '" + path + "'")
+	}
+	return name
+}
+
+func constraintString(re *regexp.Regexp) string {
+	if re == nil {
+		return ""
+	}
+	return re.String()
+}
+
+// splitPath splits the path into segments, / is [""] and /users/ is ["users", ""]
+func splitPath(path string) []string {
+	return strings.Split(strings.TrimPrefix(path, "/"), "/")
+}
+
+// lookup finds the route of the segments, static segments first, then params and catch-all segments
+// accept decides if the node of a matching route is used, otherwise the next matching route is tried
+func (n *radixNode) lookup(segments []string, params radixParams, accept func(*radixNode) bool) (*radixNode, radixParams) {
+	if len(segments) == 0 {
+		if n.handlers != nil && accept(n) {
+			return n, params
+		}
+		return nil, nil
+	}
+
+	segment := segments[0]
+
+	if child, ok := n.static[segment]; ok {
+		if node, ps := child.lookup(segments[1:], params, accept); node != nil {
+			return node, ps
+		}
+	}
+
+	if segment != "" {
+		for _, param := range n.params {
+			if param.constraint != nil && !param.constraint.MatchString(segment) {
+				continue
+			}
+			ps := append(params[:len(params):len(params)], radixParam{key: param.paramName, value: segment})
+			if node, ps := param.lookup(segments[1:], ps, accept); node != nil {
+				return node, ps
+			}
+		}
+	}
+
+	if n.catchAll != nil && n.catchAll.handlers != nil && accept(n.catchAll) {
+		value := "/" + strings.Join(segments, "/")
+		return n.catchAll, append(params[:len(params):len(params)], radixParam{key: n.catchAll.paramName, value: value})
+	}
+
+	return nil, nil
+}
+
+// find returns the route for the method, or any route of the path with ok false
+func (rr *radixRouter) find(method string, path string) (node *radixNode, params radixParams, ok bool) {
+	segments := splitPath(path)
+
+	node, params = rr.root.lookup(segments, nil, func(n *radixNode) bool {
+		_, found := n.handlers[method]
+		return found
+	})
+	if node != nil {
+		return node, params, true
+	}
+
+	node, params = rr.root.lookup(segments, nil, func(n *radixNode) bool {
+		return true
+	})
+	return node, params, false
+}
+
+func (rr *radixRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	node, params, ok := rr.find(r.Method, path)
+	if ok {
+		rr.serve(w, r, node.handlers[r.Method], params)
+		return
+	}
+
+	if node != nil {
+		rr.serveOtherMethod(w, r, node)
+		return
+	}
+
+	if path != "/" && rr.config.TrailingSlash != TrailingSlashStrict {
+		fixedPath := path + "/"
+		if strings.HasSuffix(path, "/") {
+			fixedPath = path[:len(path)-1]
+		}
+
+		if node, params, ok := rr.find(r.Method, fixedPath); ok {
+			if rr.config.TrailingSlash == TrailingSlashIgnore {
+				rr.serve(w, r, node.handlers[r.Method], params)
+				return
+			}
+
+			code := http.StatusMovedPermanently
+			if r.Method != http.MethodGet {
+				code = http.StatusTemporaryRedirect
+			}
+			url := *r.URL
+			url.Path = fixedPath
+			url.RawPath = ""
+			http.Redirect(w, r, url.String(), code)
+			return
+		}
+	}
+
+	if rr.notFound != nil {
+		rr.notFound(w, r)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func (rr *radixRouter) serve(w http.ResponseWriter, r *http.Request, handler http.HandlerFunc, params radixParams) {
+	if params != nil {
+		r = r.WithContext(context.WithValue(r.Context(), radixParamsKey{}, params))
+	}
+	handler(w, r)
+}
+
+// serveOtherMethod responds to the OPTIONS requests and the methods not registered for the path
+func (rr *radixRouter) serveOtherMethod(w http.ResponseWriter, r *http.Request, node *radixNode) {
+	methods := make([]string, 0, len(node.handlers)+1)
+	for method := range node.handlers {
+		methods = append(methods, method)
+	}
+	if _, ok := node.handlers[http.MethodOptions]; !ok {
+		methods = append(methods, http.MethodOptions)
+	}
+	sort.Strings(methods)
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+
+	if r.Method == http.MethodOptions {
+		if rr.globalOPTIONS != nil {
+			rr.globalOPTIONS(w, r)
+		}
+		return
+	}
+
+	if rr.methodNotAllowed != nil {
+		rr.methodNotAllowed(w, r)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+func (rr *radixRouter) ServeFiles(path string, fs http.FileSystem) {
+	if !strings.HasSuffix(path, "/*filepath") {
+		panic("path must end with /*filepath in path '" + path + "'")
+	}
+
+	fileServer := http.FileServer(fs)
+	rr.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		r.URL.Path = rr.ParamsFromContext(r.Context()).ByName("filepath")
+		fileServer.ServeHTTP(w, r)
+	})
+}
+
+func (rr *radixRouter) ParamsFromContext(ctx context.Context) Params {
+	params, _ := ctx.Value(radixParamsKey{}).(radixParams)
+	return params
+}
+
+func (rr *radixRouter) NotFound(handler http.HandlerFunc) {
+	rr.notFound = handler
+}
+
+func (rr *radixRouter) MethodNotAllowed(handler http.HandlerFunc) {
+	rr.methodNotAllowed = handler
+}
+
+func (rr *radixRouter) GlobalOPTIONS(handler http.HandlerFunc) {
+	rr.globalOPTIONS = handler
+}
+
+// Router returns nil, the radix router is not based on httprouter
+func (rr *radixRouter) Router() *httprouter.Router {
+	return nil
+}
@@ -0,0 +1,132 @@
+package gsk
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+)
+
+const (
+	HeaderRequestID = "X-Request-ID"
+
+	defaultStackSize = 8 << 10
+)
+
+// PanicInfo describes a panic recovered from a handler
+type PanicInfo struct {
+	Value     interface{}
+	Stack     []byte
+	Method    string
+	Path      string
+	Route     string
+	RequestID string
+}
+
+// PanicReporter receives the recovered panics, eg: to send them to an error tracker
+type PanicReporter func(c *Context, info PanicInfo)
+
+type RecoverConfig struct {
+	// DisableStackTrace disables capturing the stack trace of the panic
+	DisableStackTrace bool
+	// StackSize is the maximum size of the stack trace in bytes, default 8KB
+	StackSize int
+	// Reporter is called for every recovered panic after it is logged
+	Reporter PanicReporter
+}
+
+// PanicError is the error passed to the ErrorHandler for a recovered panic
+// it is written as a 500 internal_server_error by the built-in error handlers
+type PanicError struct {
+	Value interface{}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Recover returns a middleware which recovers from panics in the next handlers
+// The panic is logged with the stack trace, request id and route, reported to the
+// PanicReporter if configured, and a 500 response is written by the ErrorHandler
+// headers set by the next handlers before the panic are removed from the response
+// The server recovers from panics by default, see ServerConfig.DisableRecover
+func Recover(config ...RecoverConfig) Middleware {
+	var recoverConfig RecoverConfig
+	if len(config) > 0 {
+		recoverConfig = config[0]
+	}
+	if recoverConfig.StackSize <= 0 {
+		recoverConfig.StackSize = defaultStackSize
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			// headers set by the next handlers are dropped from the error response
+			headers := c.Writer.Header().Clone()
+
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+				// ErrAbortHandler is used to abort the response, net/http handles it silently
+				if value == http.ErrAbortHandler {
+					panic(value)
+				}
+
+				info := PanicInfo{
+					Value:     value,
+					Method:    c.Request.Method,
+					Path:      c.Request.URL.Path,
+					Route:     c.route,
+					RequestID: requestID(c),
+				}
+				if !recoverConfig.DisableStackTrace {
+					stack := make([]byte, recoverConfig.StackSize)
+					info.Stack = stack[:runtime.Stack(stack, false)]
+				}
+
+				c.logger.Error(
+					"panic recovered",
+					"error", value,
+					"method", info.Method,
+					"path", info.Path,
+					"route", info.Route,
+					"request_id", info.RequestID,
+					"stack", string(info.Stack),
+				)
+
+				if recoverConfig.Reporter != nil {
+					recoverConfig.Reporter(c, info)
+				}
+
+				if c.responseWritten {
+					return
+				}
+				c.responseStatus = 0
+				c.responseBody = nil
+				resetHeaders(c.Writer.Header(), headers)
+				c.ErrorResponse(&PanicError{Value: value})
+			}()
+
+			next(c)
+		}
+	}
+}
+
+// resetHeaders restores the response headers to the saved headers
+func resetHeaders(headers http.Header, saved http.Header) {
+	for key := range headers {
+		delete(headers, key)
+	}
+	for key, values := range saved {
+		headers[key] = values
+	}
+}
+
+// requestID returns the request id from the response or the request headers
+func requestID(c *Context) string {
+	if id := c.Writer.Header().Get(HeaderRequestID); id != "" {
+		return id
+	}
+	return c.Request.Header.Get(HeaderRequestID)
+}
@@ -0,0 +1,124 @@
+package gsk_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover(t *testing.T) {
+	newServer := func(config *gsk.ServerConfig) (*gsk.Server, *bytes.Buffer) {
+		logs := &bytes.Buffer{}
+		config.Logger = slog.New(slog.NewJSONHandler(logs, nil))
+
+		s := gsk.New(config)
+		s.Get("/users/:id", func(c *gsk.Context) {
+			c.Status(http.StatusCreated).StringResponse("partial")
+			panic("boom")
+		})
+		return s, logs
+	}
+
+	t.Run("recovers from panics by default", func(t *testing.T) {
+		s, logs := newServer(&gsk.ServerConfig{})
+
+		rr, _ := s.Test("GET", "/users/12", nil, gsk.TestParams{
+			Headers: map[string]string{gsk.HeaderRequestID: "req-1"},
+		})
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.JSONEq(t, `{"error": "internal_server_error"}`, rr.Body.String())
+
+		assert.Contains(t, logs.String(), `"msg":"panic recovered"`)
+		assert.Contains(t, logs.String(), `"error":"boom"`)
+		assert.Contains(t, logs.String(), `"route":"/users/:id"`)
+		assert.Contains(t, logs.String(), `"request_id":"req-1"`)
+		assert.Contains(t, logs.String(), "recover_test.go")
+		assert.Equal(t, 1, bytes.Count(logs.Bytes(), []byte("\n")), "panic is logged once")
+	})
+
+	t.Run("reports panics and uses the error handler", func(t *testing.T) {
+		var reported gsk.PanicInfo
+		var handled error
+		s, _ := newServer(&gsk.ServerConfig{
+			Recover: gsk.RecoverConfig{
+				DisableStackTrace: true,
+				Reporter: func(c *gsk.Context, info gsk.PanicInfo) {
+					reported = info
+				},
+			},
+			ErrorHandler: func(c *gsk.Context, err error) {
+				handled = err
+				gsk.ProblemJSONErrorHandler(c, err)
+			},
+		})
+
+		rr, _ := s.Test("GET", "/users/12", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Equal(t, gsk.MIMEProblemJSON, rr.Header().Get("Content-Type"))
+
+		assert.Equal(t, "boom", reported.Value)
+		assert.Equal(t, "/users/:id", reported.Route)
+		assert.Equal(t, "/users/12", reported.Path)
+		assert.Equal(t, http.MethodGet, reported.Method)
+		assert.Nil(t, reported.Stack)
+
+		var panicErr *gsk.PanicError
+		assert.True(t, errors.As(handled, &panicErr))
+		assert.EqualError(t, handled, "panic: boom")
+	})
+
+	t.Run("recovers panics in middlewares", func(t *testing.T) {
+		s, _ := newServer(&gsk.ServerConfig{})
+		s.Use(func(next gsk.HandlerFunc) gsk.HandlerFunc {
+			return func(c *gsk.Context) {
+				panic(errors.New("middleware failed"))
+			}
+		})
+		s.Get("/", func(c *gsk.Context) {})
+
+		rr, _ := s.Test("GET", "/", nil)
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+
+	t.Run("server middlewares see the error response", func(t *testing.T) {
+		s, logs := newServer(&gsk.ServerConfig{})
+
+		var status int
+		s.Use(func(next gsk.HandlerFunc) gsk.HandlerFunc {
+			return func(c *gsk.Context) {
+				c.SetHeader(gsk.HeaderRequestID, "req-1")
+				next(c)
+				status = c.GetStatusCode()
+			}
+		})
+		s.Get("/download", func(c *gsk.Context) {
+			c.SetHeader("Content-Disposition", "attachment; filename=report.csv")
+			c.SetHeader("ETag", `"v1"`)
+			panic("boom")
+		})
+
+		rr, _ := s.Test("GET", "/download", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, status)
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Equal(t, "req-1", rr.Header().Get(gsk.HeaderRequestID))
+		assert.Empty(t, rr.Header().Get("Content-Disposition"))
+		assert.Empty(t, rr.Header().Get("ETag"))
+		assert.Contains(t, logs.String(), `"request_id":"req-1"`)
+	})
+
+	t.Run("does not recover when disabled", func(t *testing.T) {
+		s, _ := newServer(&gsk.ServerConfig{DisableRecover: true})
+
+		assert.PanicsWithValue(t, "boom", func() {
+			s.Test("GET", "/users/12", nil)
+		})
+	})
+}
@@ -1,46 +1,64 @@
 package gsk
 
-import "strings"
+import (
+	"net/http"
+	"strings"
+)
 
+// RouteGroup registers routes under a path prefix with its own middlewares
+// the middlewares of the group and its parent groups are resolved when a request is handled,
+// so middlewares added with Use apply to the routes registered before and after it
 type RouteGroup struct {
 	server      *Server
+	parent      *RouteGroup
+	host        *hostRouter
 	pathPrefix  string
 	middlewares []Middleware
 }
 
-func (rg *RouteGroup) Use(middleware Middleware) {
-	rg.middlewares = append(rg.middlewares, middleware)
+// Use adds middlewares to the group, they run after the server and parent group middlewares
+func (rg *RouteGroup) Use(middlewares ...Middleware) {
+	rg.middlewares = append(rg.middlewares, middlewares...)
 }
 
 func (rg *RouteGroup) RouteGroup(path string) *RouteGroup {
 	path = strings.TrimSuffix(path, "/")
 	return &RouteGroup{
-		server:      rg.server,
-		pathPrefix:  rg.pathPrefix + path,
-		middlewares: rg.middlewares,
+		server:     rg.server,
+		parent:     rg,
+		host:       rg.host,
+		pathPrefix: rg.pathPrefix + path,
 	}
 }
 
-func (rg *RouteGroup) Get(path string, handler HandlerFunc) {
-	rg.server.Get(rg.pathPrefix+path, applyMiddlewares(rg.middlewares, handler))
+func (rg *RouteGroup) Get(path string, handler HandlerFunc, middlewares ...Middleware) *Route {
+	return rg.Handle(http.MethodGet, path, handler, middlewares...)
 }
 
-func (rg *RouteGroup) Post(path string, handler HandlerFunc) {
-	rg.server.Post(rg.pathPrefix+path, applyMiddlewares(rg.middlewares, handler))
+func (rg *RouteGroup) Post(path string, handler HandlerFunc, middlewares ...Middleware) *Route {
+	return rg.Handle(http.MethodPost, path, handler, middlewares...)
 }
 
-func (rg *RouteGroup) Put(path string, handler HandlerFunc) {
-	rg.server.Put(rg.pathPrefix+path, applyMiddlewares(rg.middlewares, handler))
+func (rg *RouteGroup) Put(path string, handler HandlerFunc, middlewares ...Middleware) *Route {
+	return rg.Handle(http.MethodPut, path, handler, middlewares...)
 }
 
-func (rg *RouteGroup) Delete(path string, handler HandlerFunc) {
-	rg.server.Delete(rg.pathPrefix+path, applyMiddlewares(rg.middlewares, handler))
+func (rg *RouteGroup) Delete(path string, handler HandlerFunc, middlewares ...Middleware) *Route {
+	return rg.Handle(http.MethodDelete, path, handler, middlewares...)
 }
 
-func (rg *RouteGroup) Patch(path string, handler HandlerFunc) {
-	rg.server.Patch(rg.pathPrefix+path, applyMiddlewares(rg.middlewares, handler))
+func (rg *RouteGroup) Patch(path string, handler HandlerFunc, middlewares ...Middleware) *Route {
+	return rg.Handle(http.MethodPatch, path, handler, middlewares...)
 }
 
-func (rg *RouteGroup) Handle(method string, path string, handler HandlerFunc) {
-	rg.server.Handle(method, rg.pathPrefix+path, applyMiddlewares(rg.middlewares, handler))
+func (rg *RouteGroup) Handle(method string, path string, handler HandlerFunc, middlewares ...Middleware) *Route {
+	return rg.server.handle(method, rg.pathPrefix+path, handler, rg, middlewares)
+}
+
+// chain returns the middlewares of the parent groups followed by the middlewares of the group
+func (rg *RouteGroup) chain() []Middleware {
+	if rg == nil {
+		return nil
+	}
+	return append(rg.parent.chain(), rg.middlewares...)
 }
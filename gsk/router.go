@@ -11,12 +11,19 @@ type Params interface {
 	ByName(string) string
 }
 
+// Router matches the requests to the registered handlers
+// HTTPRouter is the default implementation, RadixRouter supports typed params and static routes next to params
 type Router interface {
 	ServeFiles(string, http.FileSystem)
 	ServeHTTP(http.ResponseWriter, *http.Request)
 	HandlerFunc(method string, path string, handler http.HandlerFunc)
 	ParamsFromContext(context.Context) Params
 
+	// handlers for unmatched paths, unsupported methods and automatic OPTIONS responses
+	NotFound(handler http.HandlerFunc)
+	MethodNotAllowed(handler http.HandlerFunc)
+	GlobalOPTIONS(handler http.HandlerFunc)
+
 	Router() *httprouter.Router
 }
 
@@ -40,10 +47,34 @@ func (gr *gskRouter) ParamsFromContext(ctx context.Context) Params {
 	return httprouter.ParamsFromContext(ctx)
 }
 
+func (gr *gskRouter) NotFound(handler http.HandlerFunc) {
+	gr.router.NotFound = handler
+}
+
+// MethodNotAllowed sets the handler for paths registered with other methods
+// the Allow header is set by the router before the handler is called
+func (gr *gskRouter) MethodNotAllowed(handler http.HandlerFunc) {
+	gr.router.MethodNotAllowed = handler
+}
+
+// GlobalOPTIONS sets the handler for OPTIONS requests to paths without an OPTIONS route
+// the Allow header is set by the router before the handler is called
+func (gr *gskRouter) GlobalOPTIONS(handler http.HandlerFunc) {
+	gr.router.GlobalOPTIONS = handler
+}
+
 func (gr *gskRouter) Router() *httprouter.Router {
 	return gr.router
 }
 
+// RouterFactory creates the router of a server, see ServerConfig.Router
+type RouterFactory func() Router
+
+// HTTPRouter creates the default router based on julienschmidt/httprouter
+func HTTPRouter() Router {
+	return newGskRouter()
+}
+
 func newGskRouter() Router {
 	router := httprouter.New()
 	return &gskRouter{
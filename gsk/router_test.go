@@ -0,0 +1,261 @@
+package gsk_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+var routers = map[string]gsk.RouterFactory{
+	"httprouter": gsk.HTTPRouter,
+	"radix":      gsk.RadixRouter,
+}
+
+// TestRouterConformance runs the same routing behaviour against all the built-in routers
+func TestRouterConformance(t *testing.T) {
+	for name, factory := range routers {
+		t.Run(name, func(t *testing.T) {
+			newServer := func() *gsk.Server {
+				s := gsk.New(&gsk.ServerConfig{
+					Router: factory,
+					StaticFS: fstest.MapFS{
+						"app.css": &fstest.MapFile{Data: []byte("body {}")},
+					},
+				})
+
+				respond := func(c *gsk.Context) {
+					c.Status(http.StatusOK).StringResponse(c.Request.Method + " " + c.Route())
+				}
+
+				s.Get("/", respond)
+				s.Get("/users", respond)
+				s.Post("/users", respond)
+				s.Get("/users/:id", func(c *gsk.Context) {
+					c.Status(http.StatusOK).StringResponse("user " + c.Param("id"))
+				})
+				s.Put("/users/:id/posts/:post", func(c *gsk.Context) {
+					c.Status(http.StatusOK).StringResponse(c.Param("id") + " " + c.Param("post"))
+				})
+				s.Get("/files/*path", func(c *gsk.Context) {
+					c.Status(http.StatusOK).StringResponse("file " + c.Param("path"))
+				})
+				return s
+			}
+
+			testCases := []struct {
+				name     string
+				method   string
+				path     string
+				status   int
+				body     string
+				location string
+				allow    string
+			}{
+				{name: "root", method: "GET", path: "/", status: http.StatusOK, body: "GET /"},
+				{name: "static route", method: "GET", path: "/users", status: http.StatusOK, body: "GET /users"},
+				{name: "method of static route", method: "POST", path: "/users", status: http.StatusOK, body: "POST /users"},
+				{name: "param", method: "GET", path: "/users/12", status: http.StatusOK, body: "user 12"},
+				{name: "multiple params", method: "PUT", path: "/users/12/posts/3", status: http.StatusOK, body: "12 3"},
+				{name: "catch-all", method: "GET", path: "/files/docs/readme.md", status: http.StatusOK, body: "file /docs/readme.md"},
+				{name: "catch-all with trailing slash", method: "GET", path: "/files/", status: http.StatusOK, body: "file /"},
+				{name: "static files", method: "GET", path: "/static/app.css", status: http.StatusOK, body: "body {}"},
+				{name: "not found", method: "GET", path: "/orders", status: http.StatusNotFound, body: "404 page not found\n"},
+				{name: "method not allowed", method: "DELETE", path: "/users", status: http.StatusMethodNotAllowed, body: "Method Not Allowed\n", allow: "GET, OPTIONS, POST"},
+				{name: "automatic options", method: "OPTIONS", path: "/users/12", status: http.StatusNoContent, allow: "GET, OPTIONS"},
+				{name: "trailing slash redirect for GET", method: "GET", path: "/users/?page=2", status: http.StatusMovedPermanently, location: "/users?page=2"},
+				{name: "trailing slash redirect for POST", method: "POST", path: "/users/", status: http.StatusTemporaryRedirect, location: "/users"},
+				{name: "catch-all redirect", method: "GET", path: "/files", status: http.StatusMovedPermanently, location: "/files/"},
+			}
+
+			s := newServer()
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					rr, _ := s.Test(tc.method, tc.path, nil)
+					assert.Equal(t, tc.status, rr.Code)
+					if tc.body != "" {
+						assert.Equal(t, tc.body, rr.Body.String())
+					}
+					assert.Equal(t, tc.location, rr.Header().Get("Location"))
+					assert.Equal(t, tc.allow, rr.Header().Get("Allow"))
+				})
+			}
+
+			t.Run("host routes and mounts", func(t *testing.T) {
+				s := newServer()
+				s.Host(":tenant.example.com").Get("/users/:id", func(c *gsk.Context) {
+					c.Status(http.StatusOK).StringResponse(c.Param("tenant") + " " + c.Param("id"))
+				})
+				s.Mount("/legacy", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte("legacy " + r.URL.Path))
+				}))
+
+				req := httptest.NewRequest("GET", "/users/7", nil)
+				req.Host = "acme.example.com"
+				rr := httptest.NewRecorder()
+				s.ServeHTTP(rr, req)
+				assert.Equal(t, "acme 7", rr.Body.String())
+
+				r, _ := s.Test("GET", "/legacy/users", nil)
+				assert.Equal(t, "legacy /users", r.Body.String())
+			})
+
+			t.Run("panics on duplicate routes", func(t *testing.T) {
+				s := newServer()
+				assert.Panics(t, func() {
+					s.Get("/users", func(c *gsk.Context) {})
+				})
+			})
+		})
+	}
+}
+
+func TestRadixRouter(t *testing.T) {
+	respond := func(body string) gsk.HandlerFunc {
+		return func(c *gsk.Context) {
+			c.Status(http.StatusOK).StringResponse(body)
+		}
+	}
+
+	t.Run("static segments take priority over params", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{Router: gsk.RadixRouter})
+		s.Get("/users/:id", func(c *gsk.Context) {
+			c.Status(http.StatusOK).StringResponse("user " + c.Param("id"))
+		})
+		s.Get("/users/new", respond("new user form"))
+		s.Get("/users/new/edit", respond("edit new"))
+		s.Get("/users/:id/posts", func(c *gsk.Context) {
+			c.Status(http.StatusOK).StringResponse("posts of " + c.Param("id"))
+		})
+		s.Get("/users/*rest", func(c *gsk.Context) {
+			c.Status(http.StatusOK).StringResponse("rest " + c.Param("rest"))
+		})
+
+		testCases := []struct {
+			path string
+			body string
+		}{
+			{"/users/new", "new user form"},
+			{"/users/12", "user 12"},
+			{"/users/new/edit", "edit new"},
+			{"/users/new/posts", "posts of new"},
+			{"/users/12/comments", "rest /12/comments"},
+		}
+
+		for _, tc := range testCases {
+			rr, _ := s.Test("GET", tc.path, nil)
+			assert.Equal(t, http.StatusOK, rr.Code, tc.path)
+			assert.Equal(t, tc.body, rr.Body.String(), tc.path)
+		}
+	})
+
+	t.Run("matches the method over the route priority", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{Router: gsk.RadixRouter})
+		s.Get("/users/new", respond("new user form"))
+		s.Delete("/users/:id", respond("deleted"))
+
+		rr, _ := s.Test("DELETE", "/users/new", nil)
+		assert.Equal(t, "deleted", rr.Body.String())
+
+		rr, _ = s.Test("POST", "/users/new", nil)
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+		assert.Equal(t, "GET, OPTIONS", rr.Header().Get("Allow"))
+	})
+
+	t.Run("matches typed and regex params", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{Router: gsk.RadixRouter})
+		s.Get("/users/{id:int}", func(c *gsk.Context) {
+			c.Status(http.StatusOK).StringResponse("id " + c.Param("id"))
+		})
+		s.Get("/users/{id:uuid}", func(c *gsk.Context) {
+			c.Status(http.StatusOK).StringResponse("uuid " + c.Param("id"))
+		})
+		s.Get("/users/{name}", func(c *gsk.Context) {
+			c.Status(http.StatusOK).StringResponse("name " + c.Param("name"))
+		})
+		s.Get("/posts/{slug:[a-z0-9-]+}", func(c *gsk.Context) {
+			c.Status(http.StatusOK).StringResponse("slug " + c.Param("slug"))
+		})
+
+		testCases := []struct {
+			path   string
+			status int
+			body   string
+		}{
+			{"/users/42", http.StatusOK, "id 42"},
+			{"/users/-1", http.StatusOK, "id -1"},
+			{"/users/3f1b8c2e-8d4a-4e6b-9f0a-1c2d3e4f5a6b", http.StatusOK, "uuid 3f1b8c2e-8d4a-4e6b-9f0a-1c2d3e4f5a6b"},
+			{"/users/adharsh", http.StatusOK, "name adharsh"},
+			{"/posts/hello-world-2", http.StatusOK, "slug hello-world-2"},
+			{"/posts/Hello", http.StatusNotFound, "404 page not found\n"},
+		}
+
+		for _, tc := range testCases {
+			rr, _ := s.Test("GET", tc.path, nil)
+			assert.Equal(t, tc.status, rr.Code, tc.path)
+			assert.Equal(t, tc.body, rr.Body.String(), tc.path)
+		}
+	})
+
+	t.Run("applies the trailing slash policy", func(t *testing.T) {
+		testCases := []struct {
+			policy   gsk.TrailingSlashPolicy
+			status   int
+			location string
+		}{
+			{gsk.TrailingSlashRedirect, http.StatusMovedPermanently, "/users"},
+			{gsk.TrailingSlashIgnore, http.StatusOK, ""},
+			{gsk.TrailingSlashStrict, http.StatusNotFound, ""},
+		}
+
+		for _, tc := range testCases {
+			s := gsk.New(&gsk.ServerConfig{Router: func() gsk.Router {
+				return gsk.NewRadixRouter(gsk.RadixRouterConfig{TrailingSlash: tc.policy})
+			}})
+			s.Get("/users", respond("users"))
+
+			rr, _ := s.Test("GET", "/users/", nil)
+			assert.Equal(t, tc.status, rr.Code)
+			assert.Equal(t, tc.location, rr.Header().Get("Location"))
+		}
+	})
+
+	t.Run("panics on invalid routes", func(t *testing.T) {
+		testCases := []struct {
+			name   string
+			routes []string
+		}{
+			{"catch-all before the end", []string{"/files/*path/raw"}},
+			{"param without name", []string{"/users/:"}},
+			{"invalid regex", []string{"/users/{id:[0-9}"}},
+			{"params with different names", []string{"/users/:id", "/users/:name/posts"}},
+		}
+
+		for _, tc := range testCases {
+			s := gsk.New(&gsk.ServerConfig{Router: gsk.RadixRouter})
+			assert.Panics(t, func() {
+				for _, route := range tc.routes {
+					s.Get(route, respond(""))
+				}
+			}, tc.name)
+		}
+	})
+
+	t.Run("documents typed params in the OpenAPI document", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{Router: gsk.RadixRouter})
+		s.Get("/users/{id:int}/posts/{slug:[a-z]+}", respond(""))
+
+		document, _ := s.OpenAPIDocument(gsk.OpenAPIConfig{})
+		operation := document["paths"].(gsk.Map)["/users/{id}/posts/{slug}"].(gsk.Map)["get"]
+		assertJSON(t, `{
+			"parameters": [
+				{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}},
+				{"name": "slug", "in": "path", "required": true, "schema": {"type": "string", "pattern": "^(?:[a-z]+)$"}}
+			],
+			"responses": {"200": {"description": "OK"}}
+		}`, operation)
+	})
+}
@@ -0,0 +1,107 @@
+package gsk
+
+import (
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Route is a route registered in the server
+type Route struct {
+	Method string
+	Path   string
+	// Handler is the name of the handler function, eg: handler.(*userHandler).GetUser
+	Handler string
+	// Group is the path prefix of the route group the route is registered in
+	Group string
+	// Host is the host pattern of the route, empty for routes of all hosts
+	Host string
+	// Middlewares are the names of the server, group and route middlewares applied to the route, in order
+	Middlewares []string
+	// Doc describes the route in the OpenAPI document
+	Doc *RouteDoc
+
+	group            *RouteGroup
+	routeMiddlewares []Middleware
+	// hidden routes are not added to the OpenAPI document
+	hidden bool
+}
+
+// RouteDoc describes a route for the OpenAPI document
+// Request is a struct using the binding tags of Bind, path, query and header fields become parameters
+// and the other fields become the JSON request body, validate tags are used as constraints
+// Responses are the response body values by status code
+type RouteDoc struct {
+	Summary     string
+	Description string
+	Tags        []string
+	OperationID string
+	Deprecated  bool
+	Request     interface{}
+	Responses   map[int]interface{}
+}
+
+// Document adds the description of the route for the OpenAPI document
+// usage example:
+//
+//	server.Post("/users", handler.CreateUser).Document(gsk.RouteDoc{
+//		Summary:   "Create a user",
+//		Request:   CreateUserRequest{},
+//		Responses: map[int]interface{}{201: User{}, 400: gsk.ValidationErrors{}},
+//	})
+func (r *Route) Document(doc RouteDoc) *Route {
+	r.Doc = &doc
+	return r
+}
+
+// HandlerName sets the name of the handler listed in the route table
+// handlers wrapped by E or other wrappers are listed by the name of the wrapper, eg: gsk.E
+// usage example:
+//
+//	server.Delete("/users/:id", gsk.E(handler.DeleteUser)).HandlerName("handler.DeleteUser")
+func (r *Route) HandlerName(name string) *Route {
+	r.Handler = name
+	return r
+}
+
+// Routes returns the registered routes in the order of registration
+func (s *Server) Routes() []Route {
+	routes := make([]Route, len(s.routes))
+	for i, route := range s.routes {
+		middlewares := append(append([]Middleware{}, s.middlewares...), route.group.chain()...)
+		routes[i] = *route
+		routes[i].Middlewares = funcNames(append(middlewares, route.routeMiddlewares...))
+	}
+	return routes
+}
+
+func funcNames(middlewares []Middleware) []string {
+	names := make([]string, len(middlewares))
+	for i, middleware := range middlewares {
+		names[i] = funcName(middleware)
+	}
+	return names
+}
+
+var closureSuffix = regexp.MustCompile(`(\.func\d+|\.\d+)+$|-fm$`)
+
+// funcName returns the package qualified name of the function, eg: middleware.CORS
+// closures are named by the function returning them
+func funcName(fn interface{}) string {
+	value := reflect.ValueOf(fn)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return ""
+	}
+
+	runtimeFunc := runtime.FuncForPC(value.Pointer())
+	if runtimeFunc == nil {
+		return ""
+	}
+
+	name := runtimeFunc.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return closureSuffix.ReplaceAllString(name, "")
+}
@@ -0,0 +1,90 @@
+package gsk_test
+
+import (
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+type userHandler struct{}
+
+func (h *userHandler) GetUser(c *gsk.Context) {}
+
+func (h *userHandler) DeleteUser(c *gsk.Context) error { return nil }
+
+func listUsersE(c *gsk.Context) error { return nil }
+
+func listUsers(c *gsk.Context) {}
+
+func authMiddleware(next gsk.HandlerFunc) gsk.HandlerFunc {
+	return next
+}
+
+func loggerMiddleware(next gsk.HandlerFunc) gsk.HandlerFunc {
+	return next
+}
+
+func TestServer_Routes(t *testing.T) {
+	t.Run("returns the routes in the order of registration", func(t *testing.T) {
+		s := gsk.New()
+		s.Use(loggerMiddleware)
+
+		handler := &userHandler{}
+		s.Get("/users", listUsers)
+
+		rg := s.RouteGroup("/api")
+		rg.Use(authMiddleware)
+		rg.Get("/users/:id", handler.GetUser)
+		rg.Delete("/users/:id/", func(c *gsk.Context) {})
+
+		routes := s.Routes()
+		assert.Len(t, routes, 3)
+
+		assert.Equal(t, "GET", routes[0].Method)
+		assert.Equal(t, "/users", routes[0].Path)
+		assert.Equal(t, "gsk_test.listUsers", routes[0].Handler)
+		assert.Equal(t, "", routes[0].Group)
+		assert.Equal(t, []string{"gsk_test.loggerMiddleware"}, routes[0].Middlewares)
+
+		assert.Equal(t, "GET", routes[1].Method)
+		assert.Equal(t, "/api/users/:id", routes[1].Path)
+		assert.Equal(t, "gsk_test.(*userHandler).GetUser", routes[1].Handler)
+		assert.Equal(t, "/api", routes[1].Group)
+		assert.Equal(t, []string{"gsk_test.loggerMiddleware", "gsk_test.authMiddleware"}, routes[1].Middlewares)
+
+		assert.Equal(t, "DELETE", routes[2].Method)
+		assert.Equal(t, "/api/users/:id", routes[2].Path)
+		assert.Equal(t, "gsk_test.TestServer_Routes", routes[2].Handler)
+	})
+
+	t.Run("names the routes of E with the handler name", func(t *testing.T) {
+		s := gsk.New()
+		handler := &userHandler{}
+		s.Get("/users/:id", gsk.E(handler.DeleteUser)).HandlerName("handler.DeleteUser")
+		s.Get("/users", gsk.E(listUsersE))
+
+		routes := s.Routes()
+		assert.Equal(t, "handler.DeleteUser", routes[0].Handler)
+		assert.Equal(t, "gsk.E", routes[1].Handler)
+	})
+
+	t.Run("includes server middlewares added after the routes", func(t *testing.T) {
+		s := gsk.New()
+		s.Get("/", listUsers)
+		s.Use(authMiddleware)
+
+		routes := s.Routes()
+		assert.Equal(t, []string{"gsk_test.authMiddleware"}, routes[0].Middlewares)
+	})
+
+	t.Run("returns copies of the routes", func(t *testing.T) {
+		s := gsk.New()
+		s.Get("/", listUsers)
+
+		routes := s.Routes()
+		routes[0].Path = "/changed"
+
+		assert.Equal(t, "/", s.Routes()[0].Path)
+	})
+}
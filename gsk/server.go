@@ -3,11 +3,16 @@ package gsk
 import (
 	"context"
 	"io"
+	"io/fs"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"time"
+
+	"github.com/adharshmk96/stk/pkg/tracing"
 )
 
 type HandlerFunc func(*Context)
@@ -16,17 +21,83 @@ type ServerConfig struct {
 	Port   string
 	Logger *slog.Logger
 	// Input
+	// BodySizeLimit is the maximum size of the request body in bytes, default 1MB, -1 disables the limit
+	// use the BodyLimit middleware to override it for routes and groups
 	BodySizeLimit int64
+	// TrustedProxies are the IPs or CIDRs of the proxies whose X-Forwarded-For and X-Real-IP headers are used by ClientIP
+	// eg: []string{"10.0.0.0/8", "127.0.0.1"}, forwarding headers are ignored by default
+	TrustedProxies []string
+	trustedProxies []*net.IPNet
+	// ShutdownDrainDelay is the time Shutdown waits after failing the readiness check before closing the listeners,
+	// so the load balancers see /readyz fail and stop sending requests, eg: 5s, no delay by default
+	ShutdownDrainDelay time.Duration
+	// FileBufferLimit is the size in bytes above which File and FileFromFS stream the file to the client
+	// instead of buffering it in the response, default 1MB, -1 disables streaming
+	// middlewares see the status and headers of streamed files but not the body
+	FileBufferLimit int64
+	// Upload limits the files of multipart requests, see UploadConfig
+	Upload UploadConfig
+	// JSONDecode configures the decoding of JSON bodies, eg: to disallow unknown fields
+	JSONDecode JSONDecodeOptions
 
 	// Static
 	StaticPath string
 	StaticDir  string
+	// StaticFS is used to serve static files instead of StaticDir when set
+	// eg: fs.Sub(embeddedFiles, "public/assets")
+	StaticFS fs.FS
+	// StaticPrecompressed serves the gzip file next to a static file, eg: app.js.gz for app.js,
+	// to the clients accepting gzip
+	StaticPrecompressed bool
+	// StaticMiddlewares are applied to the static files, eg: middleware.Compress()
+	// static files do not run through the server middlewares
+	StaticMiddlewares []Middleware
+
+	// Templates
+	// TemplateFS is used to read templates instead of the disk when set
+	// template paths are resolved relative to the root of the file system
+	TemplateFS fs.FS
+	// TemplateEngine renders templates from a parsed cache with layouts and partials
+	// when set, TemplateFS is not used by TemplateResponse
+	TemplateEngine *TemplateEngine
+
+	// Errors
+	// ErrorHandler writes the response for errors passed to ErrorResponse or returned from handlers registered with E
+	// default is JSONErrorHandler, use ProblemJSONErrorHandler for RFC 7807 responses
+	ErrorHandler ErrorHandler
+
+	// Router creates the router matching the requests to the routes, default HTTPRouter
+	// use RadixRouter for typed params, eg: /users/{id:int}, and static routes next to params
+	Router RouterFactory
+
+	// Recovery
+	// DisableRecover disables recovering from panics in the handlers and middlewares
+	DisableRecover bool
+	// Recover configures the panic recovery, eg: to report panics with a PanicReporter
+	Recover RecoverConfig
+
+	// Tracing
+	// Tracer starts a span for every request with the route, method and status
+	// use an adapter over the OpenTelemetry SDK to export the spans
+	Tracer tracing.Tracer
 }
 
 type Server struct {
 	httpServer  *http.Server
 	router      Router
+	hosts       []*hostRouter
 	middlewares []Middleware
+	// handlers for unsupported methods and OPTIONS requests, shared by the host routers
+	methodNotAllowed http.HandlerFunc
+	globalOptions    http.HandlerFunc
+	// recoverer recovers from panics, nil when disabled
+	recoverer Middleware
+	// requestTracer starts a span for every request, nil when no tracer is configured
+	requestTracer Middleware
+	// routes are the registered routes in the order of registration
+	routes []*Route
+	// shuttingDown fails the readiness check once Shutdown is called
+	shuttingDown atomic.Bool
 	// configurations
 	config *ServerConfig
 }
@@ -40,20 +111,32 @@ func New(userconfig ...*ServerConfig) *Server {
 	config := initConfig(userconfig...)
 
 	startingPort := NormalizePort(config.Port)
-	router := newGskRouter()
-
-	// Serve static files
-	router.ServeFiles(config.StaticPath+"/*filepath", http.Dir(config.StaticDir))
+	router := config.Router()
 
 	newSTKServer := &Server{
 		httpServer: &http.Server{
-			Addr:    startingPort,
-			Handler: router,
+			Addr: startingPort,
 		},
 		router:      router,
 		middlewares: []Middleware{},
 		config:      config,
 	}
+	newSTKServer.httpServer.Handler = newSTKServer
+
+	// Serve static files
+	newSTKServer.serveStatic()
+
+	if !config.DisableRecover {
+		newSTKServer.recoverer = Recover(config.Recover)
+	}
+
+	if config.Tracer != nil {
+		newSTKServer.requestTracer = traceRequest(config.Tracer)
+	}
+
+	newSTKServer.NotFound(notFoundHandler)
+	newSTKServer.MethodNotAllowed(methodNotAllowedHandler)
+	newSTKServer.GlobalOptions(preFlightHandler)
 
 	return newSTKServer
 }
@@ -70,6 +153,7 @@ func (s *Server) Start() {
 }
 
 // Shuts down the server, use for graceful shutdown
+// /readyz fails first, the listeners are closed after ServerConfig.ShutdownDrainDelay
 // Eg Usage:
 /*
 // indicate that the server is shutting down
@@ -94,58 +178,126 @@ return server, done
 */
 func (s *Server) Shutdown() error {
 	s.config.Logger.Info("shutting down server")
+	s.shuttingDown.Store(true)
+
+	// keeps serving while the readiness probes see the server shutting down
+	if s.config.ShutdownDrainDelay > 0 {
+		time.Sleep(s.config.ShutdownDrainDelay)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	return s.httpServer.Shutdown(ctx)
 }
 
-// Use adds a middleware to the server
+// Use adds middlewares to the server
+// server middlewares run for every route in the order they are added, before the route group
+// and route middlewares, regardless of whether the routes are registered before or after Use
+// usage example:
+// server.Use(middleware.RequestLogger, middleware.CORS())
+func (s *Server) Use(middlewares ...Middleware) {
+	s.middlewares = append(s.middlewares, middlewares...)
+}
+
+// NotFound sets the handler for requests which do not match any route
+// the handler runs through the server middlewares, default responds with 404 "404 page not found"
 // usage example:
-// server.Use(stk.RequestLogger())
-// NOTE: Middlewares will be applied when the route is registered
-// SO Make sure to register the routes after adding the middlewares
-func (s *Server) Use(mw Middleware) {
-	s.middlewares = append(s.middlewares, mw)
-
-	// Add preflight handler if CORS middleware is used
-	// this is a hack to make sure that the preflight handler works with CORS Middleware
-	router := s.router.Router()
-	router.GlobalOPTIONS = wrapHandlerFunc(s, applyMiddlewares(s.middlewares, preFlightHandler))
+// server.NotFound(func(c *gsk.Context) { c.ErrorResponse(gsk.NewHTTPError(http.StatusNotFound, "not_found", "page not found")) })
+func (s *Server) NotFound(handler HandlerFunc) {
+	s.router.NotFound(wrapHandlerFunc(s, "", handler))
+}
+
+// MethodNotAllowed sets the handler for requests to a path registered only with other methods
+// the Allow header lists the registered methods, default responds with 405 "Method Not Allowed"
+func (s *Server) MethodNotAllowed(handler HandlerFunc) {
+	s.methodNotAllowed = wrapHandlerFunc(s, "", handler)
+	s.router.MethodNotAllowed(s.methodNotAllowed)
+}
+
+// GlobalOptions sets the handler for OPTIONS requests to paths without an OPTIONS route
+// the Allow header lists the registered methods, default responds with 204 so middlewares like CORS can answer preflight requests
+func (s *Server) GlobalOptions(handler HandlerFunc) {
+	s.globalOptions = wrapHandlerFunc(s, "", handler)
+	s.router.GlobalOPTIONS(s.globalOptions)
 }
 
 // Register handlers for the HTTP methods
+// middlewares passed with the handler are applied only to the route, after the server and group middlewares
+// the registered route is returned, it can be documented for the OpenAPI document
 // usage example:
 // server.Get("/test", func(c stk.Context) { gc.Status(http.StatusOK).JSONResponse("OK") })
-func (s *Server) Get(path string, handler HandlerFunc) {
-	s.Handle(http.MethodGet, path, handler)
+// server.Delete("/users/:id", handler.DeleteUser, middleware.Auth, middleware.AdminOnly)
+func (s *Server) Get(path string, handler HandlerFunc, middlewares ...Middleware) *Route {
+	return s.Handle(http.MethodGet, path, handler, middlewares...)
 }
 
-func (s *Server) Post(path string, handler HandlerFunc) {
-	s.Handle(http.MethodPost, path, handler)
+func (s *Server) Post(path string, handler HandlerFunc, middlewares ...Middleware) *Route {
+	return s.Handle(http.MethodPost, path, handler, middlewares...)
 }
 
-func (s *Server) Put(path string, handler HandlerFunc) {
-	s.Handle(http.MethodPut, path, handler)
+func (s *Server) Put(path string, handler HandlerFunc, middlewares ...Middleware) *Route {
+	return s.Handle(http.MethodPut, path, handler, middlewares...)
 }
 
-func (s *Server) Delete(path string, handler HandlerFunc) {
-	s.Handle(http.MethodDelete, path, handler)
+func (s *Server) Delete(path string, handler HandlerFunc, middlewares ...Middleware) *Route {
+	return s.Handle(http.MethodDelete, path, handler, middlewares...)
 }
 
-func (s *Server) Patch(path string, handler HandlerFunc) {
-	s.Handle(http.MethodPatch, path, handler)
+func (s *Server) Patch(path string, handler HandlerFunc, middlewares ...Middleware) *Route {
+	return s.Handle(http.MethodPatch, path, handler, middlewares...)
 }
 
 func preFlightHandler(gc *Context) {
 	gc.Status(http.StatusNoContent)
 }
 
-func (s *Server) Handle(method string, path string, handler HandlerFunc) {
+func notFoundHandler(gc *Context) {
+	gc.Writer.Header().Set("X-Content-Type-Options", "nosniff")
+	gc.Status(http.StatusNotFound).StringResponse("404 page not found\n")
+}
+
+func methodNotAllowedHandler(gc *Context) {
+	gc.Writer.Header().Set("X-Content-Type-Options", "nosniff")
+	gc.Status(http.StatusMethodNotAllowed).StringResponse(http.StatusText(http.StatusMethodNotAllowed) + "\n")
+}
+
+func (s *Server) Handle(method string, path string, handler HandlerFunc, middlewares ...Middleware) *Route {
+	return s.handle(method, path, handler, nil, middlewares)
+}
+
+// handle registers the handler with the group and route middlewares and records the route
+// the group middlewares are resolved on each request, so the ones added to the group later are applied
+func (s *Server) handle(method string, path string, handler HandlerFunc, group *RouteGroup, middlewares []Middleware) *Route {
 	if path != "/" {
 		path = strings.TrimSuffix(path, "/")
 	}
-	s.router.HandlerFunc(method, path, wrapHandlerFunc(s, handler))
+
+	route := &Route{
+		Method:           method,
+		Path:             path,
+		Handler:          funcName(handler),
+		group:            group,
+		routeMiddlewares: middlewares,
+	}
+	if group != nil {
+		route.Group = group.pathPrefix
+		if group.host != nil {
+			route.Host = group.host.pattern
+		}
+	}
+	s.routes = append(s.routes, route)
+
+	routeHandler := applyMiddlewares(middlewares, handler)
+	if group != nil {
+		withRouteMiddlewares := routeHandler
+		routeHandler = func(c *Context) {
+			applyMiddlewares(group.chain(), withRouteMiddlewares)(c)
+		}
+	}
+
+	s.routerFor(group).HandlerFunc(method, path, wrapHandlerFunc(s, path, routeHandler))
+	return route
 }
 
 // RouteGroup returns a new RouteGroup instance
@@ -202,34 +354,60 @@ func (s *Server) Test(method string, route string, body io.Reader, testParams ..
 	if err != nil {
 		return *w, err
 	}
-	s.router.ServeHTTP(w, req)
+	s.ServeHTTP(w, req)
 	return *w, nil
 }
 
 // wrapHandlerFunc wraps the handler function with the router.Handle
 // this is done to pass the gsk context to the handler function
-func wrapHandlerFunc(s *Server, handler HandlerFunc) http.HandlerFunc {
-
+// route is the registered path of the handler, eg: /users/:id
+func wrapHandlerFunc(s *Server, route string, handler HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// the server middlewares are read on each request, so Use applies to the routes registered before it
+		s.serve(w, r, route, handler, s.middlewares)
+	}
+}
 
-		p := s.router.ParamsFromContext(r.Context())
-
-		handlerContext := &Context{
-			params:        p,
-			Request:       r,
-			Writer:        w,
-			logger:        s.config.Logger,
-			bodySizeLimit: s.config.BodySizeLimit,
-		}
+// serve runs the handler with the middlewares in a new gsk context and writes the response
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, route string, handler HandlerFunc, middlewares []Middleware) {
+	p := s.router.ParamsFromContext(r.Context())
+
+	hostParams, _ := r.Context().Value(hostParamsKey{}).(map[string]string)
+
+	handlerContext := &Context{
+		params:        p,
+		hostParams:    hostParams,
+		Request:       r,
+		Writer:        w,
+		logger:        s.config.Logger,
+		bodySizeLimit: s.config.BodySizeLimit,
+		config:        s.config,
+		route:         route,
+	}
+	if r.Body != nil && r.Body != http.NoBody {
+		handlerContext.Request.Body = newLimitedBody(handlerContext)
+	}
 
-		finalHandler := applyMiddlewares(s.middlewares, handler)
-		finalHandler(handlerContext)
+	finalHandler := handler
+	if s.recoverer != nil {
+		// the handler is recovered inside the middlewares, so they see the error response
+		finalHandler = s.recoverer(finalHandler)
+	}
+	finalHandler = applyMiddlewares(middlewares, finalHandler)
+	if s.recoverer != nil {
+		// recovers from panics in the middlewares
+		finalHandler = s.recoverer(finalHandler)
+	}
+	if s.requestTracer != nil {
+		finalHandler = s.requestTracer(finalHandler)
+	}
+	finalHandler(handlerContext)
 
-		ctx := handlerContext.eject()
+	ctx := handlerContext.eject()
 
-		writeResponseWithStatus(&ctx)
+	writeResponseWithStatus(&ctx)
 
-	}
+	ctx.removeMultipartFiles()
 }
 
 func writeResponseWithStatus(c *Context) {
@@ -244,6 +422,15 @@ func writeResponseWithStatus(c *Context) {
 	c.Writer.Write(c.responseBody)
 }
 
+// staticFileSystem returns the file system to serve static files from
+// StaticFS takes precedence over StaticDir
+func staticFileSystem(config *ServerConfig) http.FileSystem {
+	if config.StaticFS != nil {
+		return http.FS(config.StaticFS)
+	}
+	return http.Dir(config.StaticDir)
+}
+
 func NormalizePort(val string) string {
 	var result string
 	if strings.ContainsAny(val, ".") {
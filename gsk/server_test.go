@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
@@ -498,4 +499,155 @@ func TestServer_Static(t *testing.T) {
 		body, _ := io.ReadAll(w.Body)
 		assert.Equal(t, "test", string(body))
 	})
+
+	t.Run("serve static file from fs", func(t *testing.T) {
+		config := &gsk.ServerConfig{
+			Port: "8888",
+			StaticFS: fstest.MapFS{
+				"test.txt": &fstest.MapFile{Data: []byte("embedded")},
+			},
+		}
+		s := gsk.New(config)
+
+		w, err := s.Test("GET", gsk.DEFAULT_STATIC_PATH+"/test.txt", nil)
+		if err != nil {
+			t.Errorf("Expected no error, but got %v", err)
+		}
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		body, _ := io.ReadAll(w.Body)
+		assert.Equal(t, "embedded", string(body))
+	})
+
+	t.Run("serve precompressed static file", func(t *testing.T) {
+		config := &gsk.ServerConfig{
+			StaticPrecompressed: true,
+			StaticFS: fstest.MapFS{
+				"app.js":    &fstest.MapFile{Data: []byte("console.log(1)")},
+				"app.js.gz": &fstest.MapFile{Data: []byte("compressed")},
+				"plain.txt": &fstest.MapFile{Data: []byte("plain")},
+			},
+		}
+		s := gsk.New(config)
+		gzipParams := gsk.TestParams{Headers: map[string]string{"Accept-Encoding": "gzip"}}
+
+		w, _ := s.Test("GET", "/static/app.js", nil, gzipParams)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "text/javascript; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+		assert.Equal(t, "compressed", w.Body.String())
+
+		w, _ = s.Test("GET", "/static/app.js", nil)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+		assert.Equal(t, "console.log(1)", w.Body.String())
+
+		w, _ = s.Test("GET", "/static/plain.txt", nil, gzipParams)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "plain", w.Body.String())
+	})
+
+	t.Run("static files run only through the static middlewares", func(t *testing.T) {
+		headerMiddleware := func(name string) gsk.Middleware {
+			return func(next gsk.HandlerFunc) gsk.HandlerFunc {
+				return func(c *gsk.Context) {
+					c.SetHeader(name, "applied")
+					next(c)
+				}
+			}
+		}
+
+		s := gsk.New(&gsk.ServerConfig{
+			StaticFS: fstest.MapFS{
+				"test.txt": &fstest.MapFile{Data: []byte("embedded")},
+			},
+			StaticMiddlewares: []gsk.Middleware{headerMiddleware("X-Static")},
+		})
+		s.Use(headerMiddleware("X-Server"))
+
+		w, _ := s.Test("GET", "/static/test.txt", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "applied", w.Header().Get("X-Static"))
+		assert.Empty(t, w.Header().Get("X-Server"))
+		assert.Equal(t, "embedded", w.Body.String())
+	})
+}
+
+func TestServer_FallbackHandlers(t *testing.T) {
+	headerMiddleware := func(next gsk.HandlerFunc) gsk.HandlerFunc {
+		return func(c *gsk.Context) {
+			c.SetHeader("X-Middleware", "applied")
+			next(c)
+		}
+	}
+
+	handler := func(c *gsk.Context) {
+		c.Status(http.StatusOK).StringResponse("ok")
+	}
+
+	t.Run("default handlers respond through the server middlewares", func(t *testing.T) {
+		s := gsk.New()
+		s.Get("/users", handler)
+		s.Post("/users", handler)
+		s.Use(headerMiddleware)
+
+		rr, _ := s.Test("GET", "/missing", nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, "404 page not found\n", rr.Body.String())
+		assert.Equal(t, "applied", rr.Header().Get("X-Middleware"))
+
+		rr, _ = s.Test("DELETE", "/users", nil)
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+		assert.Equal(t, "Method Not Allowed\n", rr.Body.String())
+		assert.Equal(t, "GET, OPTIONS, POST", rr.Header().Get("Allow"))
+		assert.Equal(t, "applied", rr.Header().Get("X-Middleware"))
+
+		rr, _ = s.Test("OPTIONS", "/users", nil)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, "GET, OPTIONS, POST", rr.Header().Get("Allow"))
+		assert.Equal(t, "applied", rr.Header().Get("X-Middleware"))
+	})
+
+	t.Run("custom handlers replace the defaults", func(t *testing.T) {
+		s := gsk.New()
+		s.Use(headerMiddleware)
+		s.Get("/users", handler)
+
+		s.NotFound(func(c *gsk.Context) {
+			c.ErrorResponse(gsk.NewHTTPError(http.StatusNotFound, "not_found", "page not found"))
+		})
+		s.MethodNotAllowed(func(c *gsk.Context) {
+			c.Status(http.StatusMethodNotAllowed).JSONResponse(gsk.Map{"allow": c.Writer.Header().Get("Allow")})
+		})
+		s.GlobalOptions(func(c *gsk.Context) {
+			c.SetHeader("Cache-Control", "max-age=3600")
+			c.Status(http.StatusOK)
+		})
+
+		rr, _ := s.Test("GET", "/missing", nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.JSONEq(t, `{"error": "not_found", "message": "page not found"}`, rr.Body.String())
+		assert.Equal(t, "applied", rr.Header().Get("X-Middleware"))
+
+		rr, _ = s.Test("PUT", "/users", nil)
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+		assert.JSONEq(t, `{"allow": "GET, OPTIONS"}`, rr.Body.String())
+
+		rr, _ = s.Test("OPTIONS", "/users", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "max-age=3600", rr.Header().Get("Cache-Control"))
+		assert.Equal(t, "GET, OPTIONS", rr.Header().Get("Allow"))
+	})
+
+	t.Run("explicit OPTIONS routes take precedence", func(t *testing.T) {
+		s := gsk.New()
+		s.Handle(http.MethodOptions, "/users", func(c *gsk.Context) {
+			c.Status(http.StatusTeapot)
+		})
+
+		rr, _ := s.Test("OPTIONS", "/users", nil)
+		assert.Equal(t, http.StatusTeapot, rr.Code)
+	})
 }
@@ -0,0 +1,66 @@
+package gsk
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+const staticPathParam = "filepath"
+
+// serveStatic registers the handler for the static files under StaticPath
+// static files run only through the StaticMiddlewares, not the server middlewares
+func (s *Server) serveStatic() {
+	staticPath := s.config.StaticPath + "/*" + staticPathParam
+	fileSystem := staticFileSystem(s.config)
+	fileServer := WrapHandler(http.FileServer(fileSystem))
+
+	handler := func(c *Context) {
+		name := c.Param(staticPathParam)
+		if s.config.StaticPrecompressed {
+			c.Writer.Header().Add("Vary", "Accept-Encoding")
+			if servePrecompressed(c, fileSystem, name) {
+				return
+			}
+		}
+
+		c.Request = stripPrefix(c.Request, name)
+		fileServer(c)
+	}
+
+	s.router.HandlerFunc(http.MethodGet, staticPath, func(w http.ResponseWriter, r *http.Request) {
+		s.serve(w, r, staticPath, handler, s.config.StaticMiddlewares)
+	})
+}
+
+// servePrecompressed serves the gzip file next to the static file, eg: app.js.gz for app.js,
+// when the client accepts gzip, returns false if there is no gzip file
+func servePrecompressed(c *Context, fileSystem http.FileSystem, name string) bool {
+	if strings.HasSuffix(name, "/") || NegotiateEncoding(c.Request.Header.Get("Accept-Encoding"), []string{"gzip"}) == "" {
+		return false
+	}
+
+	f, err := fileSystem.Open(name + ".gz")
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	// the type of the original file, the compressed content can not be sniffed
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	headers := c.Writer.Header()
+	headers.Set("Content-Type", contentType)
+	headers.Set("Content-Encoding", "gzip")
+	c.serveContent(path.Base(name), info.ModTime(), info.Size(), f)
+	return true
+}
@@ -3,11 +3,18 @@ package gsk
 import (
 	"bytes"
 	"html/template"
+	"io/fs"
 )
 
 type Tpl struct {
 	TemplatePath string
 	Variables    interface{}
+
+	// Layout and Funcs are used only when rendered with a TemplateEngine
+	// Layout wraps the template, eg: "base" for layouts/base.html
+	Layout string
+	// Funcs overrides the template functions for this render, eg: csrfToken, t
+	Funcs template.FuncMap
 }
 
 type comboVariables struct {
@@ -15,8 +22,21 @@ type comboVariables struct {
 	Config map[string]interface{}
 }
 
+// Render parses the template from the disk and renders it with the variables
 func (t *Tpl) Render(configVars map[string]interface{}) ([]byte, error) {
-	tmpl, err := template.ParseFiles(t.TemplatePath)
+	return t.RenderFS(nil, configVars)
+}
+
+// RenderFS parses the template from the file system and renders it with the variables
+// if the file system is nil, the template is read from the disk
+func (t *Tpl) RenderFS(fsys fs.FS, configVars map[string]interface{}) ([]byte, error) {
+	var tmpl *template.Template
+	var err error
+	if fsys != nil {
+		tmpl, err = template.ParseFS(fsys, t.TemplatePath)
+	} else {
+		tmpl, err = template.ParseFiles(t.TemplatePath)
+	}
 	if err != nil {
 		return nil, err
 	}
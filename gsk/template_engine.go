@@ -0,0 +1,268 @@
+package gsk
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+const (
+	DEFAULT_TEMPLATE_EXTENSION    = ".html"
+	DEFAULT_TEMPLATE_LAYOUTS_DIR  = "layouts"
+	DEFAULT_TEMPLATE_PARTIALS_DIR = "partials"
+)
+
+type TemplateEngineConfig struct {
+	// FS to read the templates from, if nil templates are read from the disk
+	FS fs.FS
+	// Dir is the root directory of the templates within FS or the disk
+	// template names are paths relative to this directory, eg: "users/list.html"
+	Dir string
+	// Extension of the template files, default ".html"
+	Extension string
+
+	// LayoutsDir and PartialsDir are relative to Dir
+	// layouts and partials are available to every page template
+	LayoutsDir  string
+	PartialsDir string
+	// DefaultLayout is used when the Tpl does not specify a layout, eg: "base"
+	DefaultLayout string
+
+	// FuncMap is added to the built-in functions, and can override them
+	FuncMap template.FuncMap
+	// StaticPath is used by the asset function, defaults to the server StaticPath
+	StaticPath string
+
+	// HotReload parses the templates on every render, use it only in development
+	HotReload bool
+}
+
+// TemplateEngine parses the template directory once and renders pages from the cache
+// Pages can be wrapped in layouts and can use partials and blocks
+type TemplateEngine struct {
+	config TemplateEngineConfig
+	fsys   fs.FS
+
+	mu    sync.RWMutex
+	pages map[string]*pageTemplate
+}
+
+// pageTemplate keeps a pristine copy of the parsed template for cloning
+// html/template does not allow cloning a template after it has been executed
+type pageTemplate struct {
+	pristine *template.Template
+	ready    *template.Template
+}
+
+// NewTemplateEngine creates a template engine and parses the templates
+// Built-in template functions:
+// - asset "css/main.css" : url of the static asset, eg: /static/css/main.css
+// - csrfToken : csrf token of the request, empty unless provided by the request funcs
+// - t "key" args... : translation of the key, returns the key unless provided by the request funcs
+func NewTemplateEngine(config TemplateEngineConfig) (*TemplateEngine, error) {
+	if config.Extension == "" {
+		config.Extension = DEFAULT_TEMPLATE_EXTENSION
+	}
+	if config.LayoutsDir == "" {
+		config.LayoutsDir = DEFAULT_TEMPLATE_LAYOUTS_DIR
+	}
+	if config.PartialsDir == "" {
+		config.PartialsDir = DEFAULT_TEMPLATE_PARTIALS_DIR
+	}
+
+	fsys, err := templateFileSystem(config.FS, config.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := &TemplateEngine{
+		config: config,
+		fsys:   fsys,
+	}
+
+	if err := engine.Load(); err != nil {
+		return nil, err
+	}
+
+	return engine, nil
+}
+
+func templateFileSystem(fsys fs.FS, dir string) (fs.FS, error) {
+	if dir == "" {
+		dir = "."
+	}
+	if fsys == nil {
+		return os.DirFS(dir), nil
+	}
+	if dir == "." {
+		return fsys, nil
+	}
+	return fs.Sub(fsys, dir)
+}
+
+// Load parses all the templates and replaces the cache
+func (e *TemplateEngine) Load() error {
+	var layouts, partials, pages []string
+
+	err := fs.WalkDir(e.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(name) != e.config.Extension {
+			return nil
+		}
+
+		switch {
+		case strings.HasPrefix(name, e.config.LayoutsDir+"/"):
+			layouts = append(layouts, name)
+		case strings.HasPrefix(name, e.config.PartialsDir+"/"):
+			partials = append(partials, name)
+		default:
+			pages = append(pages, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	base := template.New("").Funcs(e.builtinFuncs()).Funcs(e.config.FuncMap)
+	for _, name := range append(layouts, partials...) {
+		if err := e.parseFile(base, name); err != nil {
+			return err
+		}
+	}
+
+	parsed := make(map[string]*pageTemplate, len(pages))
+	for _, name := range pages {
+		pristine, err := base.Clone()
+		if err != nil {
+			return err
+		}
+		if err := e.parseFile(pristine, name); err != nil {
+			return err
+		}
+		ready, err := pristine.Clone()
+		if err != nil {
+			return err
+		}
+		parsed[name] = &pageTemplate{pristine: pristine, ready: ready}
+	}
+
+	e.mu.Lock()
+	e.pages = parsed
+	e.mu.Unlock()
+
+	return nil
+}
+
+// parseFile parses the file into the template set with its path as the name
+func (e *TemplateEngine) parseFile(set *template.Template, name string) error {
+	content, err := fs.ReadFile(e.fsys, name)
+	if err != nil {
+		return err
+	}
+	_, err = set.New(name).Parse(string(content))
+	return err
+}
+
+// Render renders the page of the Tpl with the variables accessible via .Var and .Config
+// page is wrapped in Tpl.Layout or the default layout if any
+func (e *TemplateEngine) Render(tpl *Tpl, configVars map[string]interface{}) ([]byte, error) {
+	return e.render(tpl, configVars, nil)
+}
+
+func (e *TemplateEngine) render(tpl *Tpl, configVars map[string]interface{}, requestFuncs template.FuncMap) ([]byte, error) {
+	if e.config.HotReload {
+		if err := e.Load(); err != nil {
+			return nil, err
+		}
+	}
+
+	e.mu.RLock()
+	page, ok := e.pages[tpl.TemplatePath]
+	e.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, tpl.TemplatePath)
+	}
+
+	tmpl := page.ready
+	funcs := mergeFuncMaps(requestFuncs, tpl.Funcs)
+	if len(funcs) > 0 {
+		clone, err := page.pristine.Clone()
+		if err != nil {
+			return nil, err
+		}
+		tmpl = clone.Funcs(funcs)
+	}
+
+	name := tpl.TemplatePath
+	if layout := e.layoutName(tpl.Layout); layout != "" {
+		name = layout
+	}
+
+	comboVars := &comboVariables{
+		Var:    tpl.Variables,
+		Config: configVars,
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buffer, name, comboVars); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// layoutName resolves "base" to "layouts/base.html"
+func (e *TemplateEngine) layoutName(layout string) string {
+	if layout == "" {
+		layout = e.config.DefaultLayout
+	}
+	if layout == "" {
+		return ""
+	}
+	if path.Ext(layout) == "" {
+		layout += e.config.Extension
+	}
+	if !strings.HasPrefix(layout, e.config.LayoutsDir+"/") {
+		layout = path.Join(e.config.LayoutsDir, layout)
+	}
+	return layout
+}
+
+func (e *TemplateEngine) builtinFuncs() template.FuncMap {
+	return template.FuncMap{
+		"asset": func(name string) string {
+			staticPath := e.config.StaticPath
+			if staticPath == "" {
+				staticPath = DEFAULT_STATIC_PATH
+			}
+			return path.Join(staticPath, name)
+		},
+		"csrfToken": func() string {
+			return ""
+		},
+		"t": func(key string, args ...interface{}) string {
+			if len(args) == 0 {
+				return key
+			}
+			return fmt.Sprintf(key, args...)
+		},
+	}
+}
+
+func mergeFuncMaps(funcMaps ...template.FuncMap) template.FuncMap {
+	merged := template.FuncMap{}
+	for _, funcMap := range funcMaps {
+		for name, fn := range funcMap {
+			merged[name] = fn
+		}
+	}
+	return merged
+}
@@ -0,0 +1,165 @@
+package gsk_test
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+func templateTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"templates/layouts/base.html": &fstest.MapFile{Data: []byte(
+			`<title>{{ block "title" . }}Default{{ end }}</title><main>{{ block "content" . }}{{ end }}</main>{{ template "partials/footer.html" . }}`,
+		)},
+		"templates/partials/footer.html": &fstest.MapFile{Data: []byte(`<footer>{{ t "footer" }}</footer>`)},
+		"templates/index.html": &fstest.MapFile{Data: []byte(
+			`{{ define "title" }}{{ .Var.Title }}{{ end }}{{ define "content" }}<h1>{{ .Var.Title }}</h1>{{ end }}`,
+		)},
+		"templates/users/list.html": &fstest.MapFile{Data: []byte(
+			`{{ define "content" }}<ul>{{ range .Var.Users }}<li>{{ upper . }}</li>{{ end }}</ul>{{ end }}`,
+		)},
+		"templates/plain.html": &fstest.MapFile{Data: []byte(
+			`<link href="{{ asset "css/main.css" }}"><input value="{{ csrfToken }}">`,
+		)},
+		"templates/asset.html": &fstest.MapFile{Data: []byte(
+			`{{ define "content" }}<link href="{{ asset "css/main.css" }}">{{ end }}`,
+		)},
+		"templates/notes.txt": &fstest.MapFile{Data: []byte(`not a template`)},
+	}
+}
+
+func TestTemplateEngine(t *testing.T) {
+	newEngine := func(t *testing.T, config gsk.TemplateEngineConfig) *gsk.TemplateEngine {
+		config.FS = templateTestFS()
+		config.Dir = "templates"
+		config.FuncMap = template.FuncMap{"upper": strings.ToUpper}
+		engine, err := gsk.NewTemplateEngine(config)
+		assert.NoError(t, err)
+		return engine
+	}
+
+	t.Run("renders page with layout and partials", func(t *testing.T) {
+		engine := newEngine(t, gsk.TemplateEngineConfig{})
+
+		data, err := engine.Render(&gsk.Tpl{
+			TemplatePath: "index.html",
+			Layout:       "base",
+			Variables:    gsk.Map{"Title": "Home"},
+		}, gsk.DEFAULT_TEMPLATE_VARIABLES)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `<title>Home</title><main><h1>Home</h1></main><footer>footer</footer>`, string(data))
+	})
+
+	t.Run("uses default layout and keeps blocks isolated between pages", func(t *testing.T) {
+		engine := newEngine(t, gsk.TemplateEngineConfig{DefaultLayout: "base"})
+
+		data, err := engine.Render(&gsk.Tpl{
+			TemplatePath: "users/list.html",
+			Variables:    gsk.Map{"Users": []string{"a", "b"}},
+		}, gsk.DEFAULT_TEMPLATE_VARIABLES)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `<title>Default</title><main><ul><li>A</li><li>B</li></ul></main><footer>footer</footer>`, string(data))
+	})
+
+	t.Run("renders built-in functions and request functions", func(t *testing.T) {
+		engine := newEngine(t, gsk.TemplateEngineConfig{StaticPath: "/assets"})
+
+		data, err := engine.Render(&gsk.Tpl{TemplatePath: "plain.html"}, gsk.DEFAULT_TEMPLATE_VARIABLES)
+		assert.NoError(t, err)
+		assert.Equal(t, `<link href="/assets/css/main.css"><input value="">`, string(data))
+
+		// request functions can be used after the template has been executed
+		data, err = engine.Render(&gsk.Tpl{
+			TemplatePath: "plain.html",
+			Funcs:        template.FuncMap{"csrfToken": func() string { return "token" }},
+		}, gsk.DEFAULT_TEMPLATE_VARIABLES)
+		assert.NoError(t, err)
+		assert.Equal(t, `<link href="/assets/css/main.css"><input value="token">`, string(data))
+	})
+
+	t.Run("returns error for unknown template", func(t *testing.T) {
+		engine := newEngine(t, gsk.TemplateEngineConfig{})
+
+		_, err := engine.Render(&gsk.Tpl{TemplatePath: "missing.html"}, gsk.DEFAULT_TEMPLATE_VARIABLES)
+		assert.True(t, errors.Is(err, gsk.ErrTemplateNotFound))
+	})
+
+	t.Run("returns error on parsing invalid templates", func(t *testing.T) {
+		_, err := gsk.NewTemplateEngine(gsk.TemplateEngineConfig{
+			FS: fstest.MapFS{"broken.html": &fstest.MapFile{Data: []byte(`{{ .Var.Name `)}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("hot reload parses templates on every render", func(t *testing.T) {
+		fsys := fstest.MapFS{"index.html": &fstest.MapFile{Data: []byte(`v1`)}}
+		engine, err := gsk.NewTemplateEngine(gsk.TemplateEngineConfig{FS: fsys, HotReload: true})
+		assert.NoError(t, err)
+
+		fsys["index.html"] = &fstest.MapFile{Data: []byte(`v2`)}
+
+		data, err := engine.Render(&gsk.Tpl{TemplatePath: "index.html"}, gsk.DEFAULT_TEMPLATE_VARIABLES)
+		assert.NoError(t, err)
+		assert.Equal(t, "v2", string(data))
+	})
+}
+
+func TestTemplateResponseWithEngine(t *testing.T) {
+	engine, err := gsk.NewTemplateEngine(gsk.TemplateEngineConfig{
+		FS:            templateTestFS(),
+		Dir:           "templates",
+		DefaultLayout: "base",
+		FuncMap:       template.FuncMap{"upper": strings.ToUpper},
+	})
+	assert.NoError(t, err)
+
+	s := gsk.New(&gsk.ServerConfig{
+		StaticPath:     "/public",
+		TemplateEngine: engine,
+	})
+
+	i18n := func(next gsk.HandlerFunc) gsk.HandlerFunc {
+		return func(c *gsk.Context) {
+			c.AddTemplateFuncs(template.FuncMap{
+				"t": func(key string, args ...interface{}) string { return "fußzeile" },
+			})
+			next(c)
+		}
+	}
+	s.Use(i18n)
+
+	s.Get("/", func(c *gsk.Context) {
+		c.TemplateResponse(&gsk.Tpl{
+			TemplatePath: "index.html",
+			Variables:    gsk.Map{"Title": "Home"},
+		})
+	})
+
+	s.Get("/asset", func(c *gsk.Context) {
+		c.TemplateResponse(&gsk.Tpl{TemplatePath: "asset.html"})
+	})
+
+	s.Get("/missing", func(c *gsk.Context) {
+		c.TemplateResponse(&gsk.Tpl{TemplatePath: "missing.html"})
+	})
+
+	rr, _ := s.Test("GET", "/", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/html", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `<title>Home</title><main><h1>Home</h1></main><footer>fußzeile</footer>`, rr.Body.String())
+
+	rr, _ = s.Test("GET", "/asset", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `/public/css/main.css`)
+
+	rr, _ = s.Test("GET", "/missing", nil)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
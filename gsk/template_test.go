@@ -2,6 +2,7 @@ package gsk_test
 
 import (
 	"testing"
+	"testing/fstest"
 
 	"github.com/adharshmk96/stk/gsk"
 	"github.com/adharshmk96/stk/testutils"
@@ -33,3 +34,23 @@ func TestRender(t *testing.T) {
 	assert.Error(t, err)
 
 }
+
+func TestRenderFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/index.html": &fstest.MapFile{Data: []byte("Hello, {{ .Var.Name }}! {{ .Config.Static }}/main.js")},
+	}
+
+	tpl := &gsk.Tpl{
+		TemplatePath: "templates/index.html",
+		Variables:    map[string]string{"Name": "World"},
+	}
+	data, err := tpl.RenderFS(fsys, gsk.DEFAULT_TEMPLATE_VARIABLES)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello, World! /static/main.js", string(data))
+
+	tpl = &gsk.Tpl{
+		TemplatePath: "templates/missing.html",
+	}
+	_, err = tpl.RenderFS(fsys, gsk.DEFAULT_TEMPLATE_VARIABLES)
+	assert.Error(t, err)
+}
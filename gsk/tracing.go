@@ -0,0 +1,46 @@
+package gsk
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/adharshmk96/stk/pkg/tracing"
+)
+
+// traceRequest returns a middleware which starts a span for every request
+// the span continues the trace of the traceparent header and is named by the route, eg: GET /users/:id
+func traceRequest(tracer tracing.Tracer) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			ctx := c.Request.Context()
+			if tc, err := tracing.Extract(c.Request.Header); err == nil {
+				ctx = tracing.ContextWithTrace(ctx, tc)
+			}
+
+			name := c.Request.Method
+			if c.route != "" {
+				name += " " + c.route
+			}
+
+			ctx, span := tracer.Start(ctx, name,
+				tracing.String("http.request.method", c.Request.Method),
+				tracing.String("http.route", c.route),
+				tracing.String("url.path", c.Request.URL.Path),
+			)
+			defer span.End()
+
+			c.Request = c.Request.WithContext(ctx)
+
+			next(c)
+
+			status := c.responseStatus
+			if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetAttributes(tracing.Int("http.response.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.RecordError(fmt.Errorf("%d %s", status, http.StatusText(status)))
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package gsk_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/adharshmk96/stk/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestTracing(t *testing.T) {
+	tracer := tracing.NewInMemoryTracer()
+	s := gsk.New(&gsk.ServerConfig{Tracer: tracer})
+
+	var handlerTrace tracing.TraceContext
+	s.Get("/users/:id", func(c *gsk.Context) {
+		handlerTrace, _ = tracing.FromContext(c.Request.Context())
+		c.Status(http.StatusCreated)
+	})
+	s.Get("/panic", func(c *gsk.Context) {
+		panic("boom")
+	})
+
+	t.Run("starts a span with the route of the request", func(t *testing.T) {
+		tracer.Reset()
+		s.Test("GET", "/users/12", nil)
+
+		spans := tracer.Spans()
+		assert.Equal(t, 1, len(spans))
+
+		span := spans[0]
+		assert.Equal(t, "GET /users/:id", span.Name)
+		assert.Equal(t, map[string]interface{}{
+			"http.request.method":       "GET",
+			"http.route":                "/users/:id",
+			"url.path":                  "/users/12",
+			"http.response.status_code": http.StatusCreated,
+		}, span.Attributes)
+		assert.NoError(t, span.Err)
+		assert.Equal(t, span.SpanID, handlerTrace.SpanID)
+	})
+
+	t.Run("continues the trace of the traceparent header", func(t *testing.T) {
+		tracer.Reset()
+		s.Test("GET", "/users/12", nil, gsk.TestParams{
+			Headers: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		})
+
+		span := tracer.Spans()[0]
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID)
+		assert.Equal(t, "00f067aa0ba902b7", span.ParentSpanID)
+	})
+
+	t.Run("records server errors", func(t *testing.T) {
+		tracer.Reset()
+		s.Test("GET", "/panic", nil)
+
+		span := tracer.Spans()[0]
+		assert.Equal(t, http.StatusInternalServerError, span.Attributes["http.response.status_code"])
+		assert.EqualError(t, span.Err, "500 Internal Server Error")
+	})
+}
@@ -0,0 +1,331 @@
+package gsk
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultUploadMaxMemory = 32 << 20 // 32 MB
+	sniffLength            = 512
+)
+
+// UploadConfig limits the files of multipart requests
+// the whole request is limited by ServerConfig.BodySizeLimit
+type UploadConfig struct {
+	// MaxMemory is the size of the form kept in memory by FormFile and MultipartFiles,
+	// larger files are stored in temporary files which are removed after the request, default 32MB
+	MaxMemory int64
+	// MaxFileSize is the maximum size of each file in bytes, no limit other than the body size limit if 0
+	MaxFileSize int64
+	// AllowedTypes are the allowed MIME types detected from the content of the files, eg: image/png, image/*
+	// all types are allowed if empty
+	AllowedTypes []string
+}
+
+// FormFile returns the first file of the multipart form field
+// the file is checked against the upload limits, see UploadConfig
+// usage example:
+//
+//	fh, err := c.FormFile("avatar")
+//	if err != nil {
+//		c.ErrorResponse(err)
+//		return
+//	}
+//	err = c.SaveUploadedFile(fh, filepath.Join("uploads", id+".png"))
+func (c *Context) FormFile(name string) (*multipart.FileHeader, error) {
+	files, err := c.MultipartFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files[name]) == 0 {
+		return nil, ValidationErrors{{Field: name, Source: "form", Rule: "required", Message: "is required"}}
+	}
+	return files[name][0], nil
+}
+
+// MultipartFiles returns the files of the multipart form by field name
+// the files are checked against the upload limits, see UploadConfig
+func (c *Context) MultipartFiles() (map[string][]*multipart.FileHeader, error) {
+	if err := c.parseMultipartForm(); err != nil {
+		return nil, err
+	}
+
+	config := c.uploadConfig()
+	for _, files := range c.Request.MultipartForm.File {
+		for _, fh := range files {
+			if err := checkFileHeader(fh, config); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return c.Request.MultipartForm.File, nil
+}
+
+// SaveUploadedFile writes the uploaded file to the destination, creating the parent directories
+func (c *Context) SaveUploadedFile(fh *multipart.FileHeader, dst string) error {
+	src, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return err
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, src)
+	return err
+}
+
+// MultipartReader returns a reader streaming the parts of the multipart request without storing the files
+// the server upload limits are used unless a config is passed
+// usage example:
+//
+//	reader, err := c.MultipartReader()
+//	for {
+//		part, err := reader.NextPart()
+//		if err == io.EOF {
+//			break
+//		}
+//		if err != nil {
+//			c.ErrorResponse(err)
+//			return
+//		}
+//		if part.IsFile() {
+//			storage.Upload(part.FileName, part)
+//		}
+//	}
+func (c *Context) MultipartReader(config ...UploadConfig) (*UploadReader, error) {
+	uploadConfig := c.uploadConfig()
+	if len(config) > 0 {
+		uploadConfig = config[0]
+	}
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, ErrInvalidForm
+	}
+
+	return &UploadReader{reader: reader, config: uploadConfig}, nil
+}
+
+// UploadReader reads the parts of a multipart request one at a time
+type UploadReader struct {
+	reader *multipart.Reader
+	config UploadConfig
+	part   *multipart.Part
+}
+
+// UploadPart is a form field or a file of a multipart request, read the content with Read
+type UploadPart struct {
+	FormName string
+	// FileName is empty for form fields
+	FileName string
+	// ContentType is detected from the content of the file, empty for form fields
+	ContentType string
+
+	reader io.Reader
+}
+
+// NextPart returns the next part of the request, io.EOF after the last part
+// the type of a file is checked when the part is returned, and its size while it is read
+func (ur *UploadReader) NextPart() (*UploadPart, error) {
+	if ur.part != nil {
+		ur.part.Close()
+	}
+
+	part, err := ur.reader.NextPart()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, bodyError(err)
+	}
+	ur.part = part
+
+	uploadPart := &UploadPart{
+		FormName: part.FormName(),
+		FileName: part.FileName(),
+		reader:   part,
+	}
+	if uploadPart.FileName == "" {
+		return uploadPart, nil
+	}
+
+	buffered := bufio.NewReaderSize(part, sniffLength)
+	head, err := buffered.Peek(sniffLength)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, bodyError(err)
+	}
+
+	uploadPart.ContentType = detectContentType(head)
+	if !isAllowedType(uploadPart.ContentType, ur.config.AllowedTypes) {
+		return nil, unsupportedFileError(uploadPart.FileName, uploadPart.ContentType)
+	}
+
+	uploadPart.reader = buffered
+	if ur.config.MaxFileSize > 0 {
+		uploadPart.reader = &limitedFileReader{reader: buffered, remaining: ur.config.MaxFileSize, fileName: uploadPart.FileName}
+	}
+	return uploadPart, nil
+}
+
+// IsFile reports whether the part is a file
+func (p *UploadPart) IsFile() bool {
+	return p.FileName != ""
+}
+
+func (p *UploadPart) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	if err != nil && err != io.EOF {
+		return n, bodyError(err)
+	}
+	return n, err
+}
+
+// limitedFileReader fails with ErrFileTooLarge when the file is larger than the limit
+type limitedFileReader struct {
+	reader    io.Reader
+	remaining int64
+	fileName  string
+}
+
+func (lr *limitedFileReader) Read(b []byte) (int, error) {
+	if int64(len(b)) > lr.remaining+1 {
+		b = b[:lr.remaining+1]
+	}
+	n, err := lr.reader.Read(b)
+	lr.remaining -= int64(n)
+	if lr.remaining < 0 {
+		return n + int(lr.remaining), fileTooLargeError(lr.fileName, -1)
+	}
+	return n, err
+}
+
+func (c *Context) uploadConfig() UploadConfig {
+	config := c.config.Upload
+	if config.MaxMemory <= 0 {
+		config.MaxMemory = defaultUploadMaxMemory
+	}
+	return config
+}
+
+// parseMultipartForm parses the multipart form within the body size limit
+func (c *Context) parseMultipartForm() error {
+	if c.Request.MultipartForm != nil {
+		return nil
+	}
+
+	if err := c.Request.ParseMultipartForm(c.uploadConfig().MaxMemory); err != nil {
+		return bodyError(err)
+	}
+	return nil
+}
+
+// removeMultipartFiles removes the temporary files of the multipart form
+func (c *Context) removeMultipartFiles() {
+	if c.Request.MultipartForm != nil {
+		c.Request.MultipartForm.RemoveAll()
+	}
+}
+
+// bodyError converts the errors of reading the request body, ErrBodyTooLarge if the body size limit is exceeded
+func bodyError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return ErrBodyTooLarge
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return err
+	}
+	return ErrInvalidForm
+}
+
+// checkFileHeader checks the size and the detected type of the uploaded file
+func checkFileHeader(fh *multipart.FileHeader, config UploadConfig) error {
+	if config.MaxFileSize > 0 && fh.Size > config.MaxFileSize {
+		return fileTooLargeError(fh.Filename, config.MaxFileSize)
+	}
+	if len(config.AllowedTypes) == 0 {
+		return nil
+	}
+
+	file, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+
+	contentType := detectContentType(head[:n])
+	if !isAllowedType(contentType, config.AllowedTypes) {
+		return unsupportedFileError(fh.Filename, contentType)
+	}
+	return nil
+}
+
+// detectContentType returns the media type detected from the content, without params
+func detectContentType(head []byte) string {
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+// isAllowedType reports whether the media type matches the allowed types, eg: image/png or image/*
+func isAllowedType(mediaType string, allowedTypes []string) bool {
+	if len(allowedTypes) == 0 {
+		return true
+	}
+
+	for _, allowed := range allowedTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == mediaType || allowed == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func fileTooLargeError(fileName string, maxSize int64) error {
+	message := fileName + " exceeds the file size limit"
+	if maxSize > 0 {
+		message = fmt.Sprintf("%s exceeds the file size limit of %d bytes", fileName, maxSize)
+	}
+	return &HTTPError{Status: http.StatusRequestEntityTooLarge, Code: ErrFileTooLarge.Error(), Message: message, Err: ErrFileTooLarge}
+}
+
+func unsupportedFileError(fileName string, contentType string) error {
+	return &HTTPError{
+		Status:  http.StatusUnsupportedMediaType,
+		Code:    ErrUnsupportedFileType.Error(),
+		Message: fmt.Sprintf("%s has an unsupported type %s", fileName, contentType),
+		Err:     ErrUnsupportedFileType,
+	}
+}
@@ -0,0 +1,292 @@
+package gsk_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// multipartBody creates a multipart body with the fields and files, returns the body and the content type
+func multipartBody(t *testing.T, fields map[string]string, files map[string][]byte) (*bytes.Buffer, string) {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		assert.NoError(t, writer.WriteField(name, value))
+	}
+	for name, content := range files {
+		part, err := writer.CreateFormFile(name, name+".bin")
+		assert.NoError(t, err)
+		part.Write(content)
+	}
+	assert.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func uploadParams(contentType string) gsk.TestParams {
+	return gsk.TestParams{Headers: map[string]string{"Content-Type": contentType}}
+}
+
+func TestContext_FormFile(t *testing.T) {
+	t.Run("saves the uploaded file", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "uploads", "avatar.png")
+
+		s := gsk.New()
+		s.Post("/avatar", func(c *gsk.Context) {
+			fh, err := c.FormFile("avatar")
+			if err != nil {
+				c.ErrorResponse(err)
+				return
+			}
+			if err := c.SaveUploadedFile(fh, dst); err != nil {
+				c.ErrorResponse(err)
+				return
+			}
+			c.Status(http.StatusCreated).JSONResponse(gsk.Map{"name": fh.Filename, "size": fh.Size})
+		})
+
+		body, contentType := multipartBody(t, nil, map[string][]byte{"avatar": pngHeader})
+		rr, _ := s.Test("POST", "/avatar", body, uploadParams(contentType))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.JSONEq(t, `{"name": "avatar.bin", "size": 16}`, rr.Body.String())
+
+		saved, err := os.ReadFile(dst)
+		assert.NoError(t, err)
+		assert.Equal(t, pngHeader, saved)
+	})
+
+	t.Run("returns a validation error for a missing file", func(t *testing.T) {
+		s := gsk.New()
+		s.Post("/avatar", gsk.E(func(c *gsk.Context) error {
+			_, err := c.FormFile("avatar")
+			return err
+		}))
+
+		body, contentType := multipartBody(t, map[string]string{"name": "gopher"}, nil)
+		rr, _ := s.Test("POST", "/avatar", body, uploadParams(contentType))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"error": "validation_failed", "fields": [{"field": "avatar", "source": "form", "rule": "required", "message": "is required"}]}`, rr.Body.String())
+	})
+
+	t.Run("rejects requests which are not multipart", func(t *testing.T) {
+		s := gsk.New()
+		s.Post("/avatar", gsk.E(func(c *gsk.Context) error {
+			_, err := c.FormFile("avatar")
+			return err
+		}))
+
+		rr, _ := s.Test("POST", "/avatar", strings.NewReader(`{}`), uploadParams("application/json"))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"error": "invalid_form"}`, rr.Body.String())
+	})
+
+	t.Run("enforces the upload limits", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			config  gsk.UploadConfig
+			content []byte
+			status  int
+			err     error
+		}{
+			{
+				name:    "allowed type",
+				config:  gsk.UploadConfig{AllowedTypes: []string{"image/*"}},
+				content: pngHeader,
+				status:  http.StatusOK,
+			},
+			{
+				name:    "type detected from the content",
+				config:  gsk.UploadConfig{AllowedTypes: []string{"image/png"}},
+				content: []byte("plain text pretending to be a png"),
+				status:  http.StatusUnsupportedMediaType,
+				err:     gsk.ErrUnsupportedFileType,
+			},
+			{
+				name:    "file size limit",
+				config:  gsk.UploadConfig{MaxFileSize: 8},
+				content: pngHeader,
+				status:  http.StatusRequestEntityTooLarge,
+				err:     gsk.ErrFileTooLarge,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var handlerErr error
+				s := gsk.New(&gsk.ServerConfig{Upload: tt.config})
+				s.Post("/avatar", func(c *gsk.Context) {
+					_, handlerErr = c.FormFile("avatar")
+					if handlerErr != nil {
+						c.ErrorResponse(handlerErr)
+					}
+				})
+
+				body, contentType := multipartBody(t, nil, map[string][]byte{"avatar": tt.content})
+				rr, _ := s.Test("POST", "/avatar", body, uploadParams(contentType))
+
+				assert.Equal(t, tt.status, rr.Code)
+				if tt.err != nil {
+					assert.True(t, errors.Is(handlerErr, tt.err))
+				} else {
+					assert.NoError(t, handlerErr)
+				}
+			})
+		}
+	})
+
+	t.Run("respects the body size limit", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{BodySizeLimit: 1 << 20})
+		s.Post("/avatar", gsk.E(func(c *gsk.Context) error {
+			_, err := c.FormFile("avatar")
+			return err
+		}))
+
+		body, contentType := multipartBody(t, nil, map[string][]byte{"avatar": bytes.Repeat([]byte("a"), 2<<20)})
+		rr, _ := s.Test("POST", "/avatar", body, uploadParams(contentType))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("removes the temporary files after the request", func(t *testing.T) {
+		var tempFile string
+
+		s := gsk.New(&gsk.ServerConfig{Upload: gsk.UploadConfig{MaxMemory: 1}})
+		s.Post("/avatar", func(c *gsk.Context) {
+			fh, err := c.FormFile("avatar")
+			if err != nil {
+				c.ErrorResponse(err)
+				return
+			}
+			file, _ := fh.Open()
+			defer file.Close()
+			if f, ok := file.(*os.File); ok {
+				tempFile = f.Name()
+			}
+		})
+
+		body, contentType := multipartBody(t, nil, map[string][]byte{"avatar": bytes.Repeat(pngHeader, 64)})
+		rr, _ := s.Test("POST", "/avatar", body, uploadParams(contentType))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NotEmpty(t, tempFile)
+		_, err := os.Stat(tempFile)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestContext_MultipartFiles(t *testing.T) {
+	s := gsk.New()
+	s.Post("/files", func(c *gsk.Context) {
+		files, err := c.MultipartFiles()
+		if err != nil {
+			c.ErrorResponse(err)
+			return
+		}
+		names := []string{}
+		for name := range files {
+			names = append(names, name)
+		}
+		c.JSONResponse(gsk.Map{"count": len(names)})
+	})
+
+	body, contentType := multipartBody(t, nil, map[string][]byte{"first": pngHeader, "second": []byte("hello")})
+	rr, _ := s.Test("POST", "/files", body, uploadParams(contentType))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"count": 2}`, rr.Body.String())
+}
+
+func TestContext_MultipartReader(t *testing.T) {
+	streamHandler := func(c *gsk.Context, config ...gsk.UploadConfig) error {
+		reader, err := c.MultipartReader(config...)
+		if err != nil {
+			return err
+		}
+
+		parts := gsk.Map{}
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			content, err := io.ReadAll(part)
+			if err != nil {
+				return err
+			}
+			if part.IsFile() {
+				parts[part.FormName] = gsk.Map{"file": part.FileName, "type": part.ContentType, "size": len(content)}
+			} else {
+				parts[part.FormName] = string(content)
+			}
+		}
+		c.JSONResponse(parts)
+		return nil
+	}
+
+	t.Run("streams the fields and files", func(t *testing.T) {
+		s := gsk.New()
+		s.Post("/stream", gsk.E(func(c *gsk.Context) error {
+			return streamHandler(c)
+		}))
+
+		body, contentType := multipartBody(t, map[string]string{"name": "gopher"}, map[string][]byte{"avatar": pngHeader})
+		rr, _ := s.Test("POST", "/stream", body, uploadParams(contentType))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"name": "gopher", "avatar": {"file": "avatar.bin", "type": "image/png", "size": 16}}`, rr.Body.String())
+	})
+
+	t.Run("uses the config passed to the reader", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{Upload: gsk.UploadConfig{AllowedTypes: []string{"image/png"}}})
+		s.Post("/stream", gsk.E(func(c *gsk.Context) error {
+			return streamHandler(c, gsk.UploadConfig{AllowedTypes: []string{"text/plain"}})
+		}))
+
+		body, contentType := multipartBody(t, nil, map[string][]byte{"avatar": pngHeader})
+		rr, _ := s.Test("POST", "/stream", body, uploadParams(contentType))
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	})
+
+	t.Run("stops reading files larger than the limit", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{Upload: gsk.UploadConfig{MaxFileSize: 1024}})
+		s.Post("/stream", gsk.E(func(c *gsk.Context) error {
+			return streamHandler(c)
+		}))
+
+		body, contentType := multipartBody(t, nil, map[string][]byte{"avatar": bytes.Repeat([]byte("a"), 2048)})
+		rr, _ := s.Test("POST", "/stream", body, uploadParams(contentType))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+		assert.Contains(t, rr.Body.String(), "file_too_large")
+	})
+
+	t.Run("respects the body size limit", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{BodySizeLimit: 1 << 20})
+		s.Post("/stream", gsk.E(func(c *gsk.Context) error {
+			return streamHandler(c)
+		}))
+
+		body, contentType := multipartBody(t, nil, map[string][]byte{"avatar": bytes.Repeat([]byte("a"), 2<<20)})
+		rr, _ := s.Test("POST", "/stream", body, uploadParams(contentType))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+}
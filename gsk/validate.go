@@ -0,0 +1,259 @@
+package gsk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError describes an invalid field of the request
+type FieldError struct {
+	Field   string `json:"field"`
+	Source  string `json:"source,omitempty"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is the list of invalid fields returned by Bind and Validate
+// It renders as {"error": "validation_failed", "fields": [...]}
+type ValidationErrors []FieldError
+
+func (ve ValidationErrors) Error() string {
+	messages := make([]string, len(ve))
+	for i, fe := range ve {
+		messages[i] = fe.Field + " " + fe.Message
+	}
+	return ErrValidation.Error() + ": " + strings.Join(messages, ", ")
+}
+
+// Is reports ValidationErrors as ErrValidation for errors.Is
+func (ve ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// StatusCode returns the http status of the validation errors
+func (ve ValidationErrors) StatusCode() int {
+	return 400
+}
+
+func (ve ValidationErrors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Map{
+		"error":  ErrValidation.Error(),
+		"fields": []FieldError(ve),
+	})
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Validate validates the struct pointed by v using the validate struct tags
+// Available rules:
+// - required     : value must not be the zero value
+// - min=n, max=n : minimum and maximum for numbers, length for strings, slices and maps
+// - len=n        : exact length for strings, slices and maps
+// - email, url, uuid : format of strings
+// - oneof=a b c  : value must be one of the space separated values
+// Rules other than required are skipped for empty strings, slices, maps and nil pointers
+// Nested structs and slices of structs are validated, fields are named by their binding tags
+func Validate(v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("gsk: validate target must be a struct, got %T", v)
+	}
+
+	var errs ValidationErrors
+	if err := validateStruct(rv, "", &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateStruct(rv reflect.Value, prefix string, errs *ValidationErrors) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		value := rv.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := validateStruct(value, prefix, errs); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := prefix + fieldName(field)
+		if err := validateField(value, name, field.Tag.Get("validate"), errs); err != nil {
+			return err
+		}
+		if err := validateNested(value, name, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateNested validates structs, pointers to structs and slices of structs
+func validateNested(value reflect.Value, name string, errs *ValidationErrors) error {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil
+		}
+		return validateNested(value.Elem(), name, errs)
+	case reflect.Struct:
+		if value.Type() == timeType {
+			return nil
+		}
+		return validateStruct(value, name+".", errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := validateNested(value.Index(i), fmt.Sprintf("%s[%d]", name, i), errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateField(value reflect.Value, name string, tag string, errs *ValidationErrors) error {
+	if tag == "" || tag == "-" {
+		return nil
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		rule, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		if rule == "required" {
+			if value.IsZero() {
+				*errs = append(*errs, FieldError{Field: name, Rule: rule, Message: "is required"})
+				return nil
+			}
+			continue
+		}
+
+		if isEmptyValue(value) {
+			return nil
+		}
+
+		message, err := checkRule(reflect.Indirect(value), rule, param)
+		if err != nil {
+			return fmt.Errorf("gsk: field %s: %w", name, err)
+		}
+		if message != "" {
+			*errs = append(*errs, FieldError{Field: name, Rule: rule, Param: param, Message: message})
+		}
+	}
+
+	return nil
+}
+
+// checkRule returns a message if the value does not satisfy the rule
+func checkRule(value reflect.Value, rule string, param string) (string, error) {
+	switch rule {
+	case "min", "max", "len":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid parameter %q for rule %s", param, rule)
+		}
+		return checkSize(value, rule, limit)
+
+	case "email":
+		address, err := mail.ParseAddress(value.String())
+		if err != nil || address.Address != value.String() {
+			return "must be a valid email address", nil
+		}
+	case "url":
+		u, err := url.ParseRequestURI(value.String())
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be a valid url", nil
+		}
+	case "uuid":
+		if !uuidPattern.MatchString(value.String()) {
+			return "must be a valid uuid", nil
+		}
+	case "oneof":
+		options := strings.Fields(param)
+		actual := fmt.Sprint(value.Interface())
+		for _, option := range options {
+			if option == actual {
+				return "", nil
+			}
+		}
+		return "must be one of " + strings.Join(options, ", "), nil
+	default:
+		return "", fmt.Errorf("unknown validation rule %q", rule)
+	}
+
+	return "", nil
+}
+
+func checkSize(value reflect.Value, rule string, limit float64) (string, error) {
+	var size float64
+	unit := ""
+
+	switch value.Kind() {
+	case reflect.String:
+		size = float64(utf8.RuneCountInString(value.String()))
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		size = float64(value.Len())
+		unit = " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = value.Float()
+	default:
+		return "", fmt.Errorf("rule %s is not supported for %s", rule, value.Type())
+	}
+
+	formatted := strconv.FormatFloat(limit, 'f', -1, 64) + unit
+	switch {
+	case rule == "min" && size < limit:
+		return "must be at least " + formatted, nil
+	case rule == "max" && size > limit:
+		return "must be at most " + formatted, nil
+	case rule == "len" && size != limit:
+		return "must be exactly " + formatted, nil
+	}
+	return "", nil
+}
+
+// isEmptyValue reports whether the value is absent, rules are not checked for absent values
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return value.IsNil()
+	}
+	return false
+}
+
+// fieldName returns the name of the field as seen in the request
+func fieldName(field reflect.StructField) string {
+	for _, source := range append([]string{"json"}, bindSources...) {
+		name, ok := field.Tag.Lookup(source)
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
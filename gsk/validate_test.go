@@ -0,0 +1,167 @@
+package gsk_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/stretchr/testify/assert"
+)
+
+type validateAddress struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" validate:"len=5"`
+}
+
+type validateUser struct {
+	Name      string            `json:"name" validate:"required,min=2,max=10"`
+	Email     string            `json:"email" validate:"email"`
+	Website   string            `json:"website" validate:"url"`
+	ID        string            `json:"id" validate:"uuid"`
+	Role      string            `json:"role" validate:"oneof=admin user"`
+	Age       int               `json:"age" validate:"min=18"`
+	Score     float64           `json:"score" validate:"max=1.5"`
+	Tags      []string          `json:"tags" validate:"max=2"`
+	Nickname  *string           `json:"nickname" validate:"min=3"`
+	Address   validateAddress   `json:"address"`
+	Previous  []validateAddress `json:"previous"`
+	unchecked string            `validate:"required"`
+}
+
+func validUser() validateUser {
+	return validateUser{
+		Name:     "John",
+		Email:    "john@example.com",
+		Website:  "https://example.com",
+		ID:       "123e4567-e89b-12d3-a456-426614174000",
+		Role:     "admin",
+		Age:      30,
+		Score:    1,
+		Tags:     []string{"a"},
+		Address:  validateAddress{City: "Kochi", Zip: "68200"},
+		Previous: []validateAddress{{City: "Delhi", Zip: "11000"}},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("valid struct passes", func(t *testing.T) {
+		user := validUser()
+		assert.NoError(t, gsk.Validate(&user))
+		assert.NoError(t, gsk.Validate(user))
+	})
+
+	t.Run("optional fields are not validated when empty", func(t *testing.T) {
+		user := validUser()
+		user.Email = ""
+		user.Website = ""
+		user.Tags = nil
+		user.Nickname = nil
+		assert.NoError(t, gsk.Validate(&user))
+	})
+
+	invalid := func(modify func(u *validateUser)) gsk.ValidationErrors {
+		user := validUser()
+		modify(&user)
+		err := gsk.Validate(&user)
+
+		var verrs gsk.ValidationErrors
+		assert.True(t, errors.As(err, &verrs))
+		assert.True(t, errors.Is(err, gsk.ErrValidation))
+		return verrs
+	}
+
+	short := "ab"
+
+	testCases := []struct {
+		name     string
+		modify   func(u *validateUser)
+		expected gsk.FieldError
+	}{
+		{
+			name:     "required",
+			modify:   func(u *validateUser) { u.Name = "" },
+			expected: gsk.FieldError{Field: "name", Rule: "required", Message: "is required"},
+		},
+		{
+			name:     "min length",
+			modify:   func(u *validateUser) { u.Name = "J" },
+			expected: gsk.FieldError{Field: "name", Rule: "min", Param: "2", Message: "must be at least 2 characters"},
+		},
+		{
+			name:     "max length",
+			modify:   func(u *validateUser) { u.Name = "Johnathan Doe" },
+			expected: gsk.FieldError{Field: "name", Rule: "max", Param: "10", Message: "must be at most 10 characters"},
+		},
+		{
+			name:     "email",
+			modify:   func(u *validateUser) { u.Email = "John <john@example.com>" },
+			expected: gsk.FieldError{Field: "email", Rule: "email", Message: "must be a valid email address"},
+		},
+		{
+			name:     "url",
+			modify:   func(u *validateUser) { u.Website = "example.com" },
+			expected: gsk.FieldError{Field: "website", Rule: "url", Message: "must be a valid url"},
+		},
+		{
+			name:     "uuid",
+			modify:   func(u *validateUser) { u.ID = "123" },
+			expected: gsk.FieldError{Field: "id", Rule: "uuid", Message: "must be a valid uuid"},
+		},
+		{
+			name:     "oneof",
+			modify:   func(u *validateUser) { u.Role = "root" },
+			expected: gsk.FieldError{Field: "role", Rule: "oneof", Param: "admin user", Message: "must be one of admin, user"},
+		},
+		{
+			name:     "min number",
+			modify:   func(u *validateUser) { u.Age = 0 },
+			expected: gsk.FieldError{Field: "age", Rule: "min", Param: "18", Message: "must be at least 18"},
+		},
+		{
+			name:     "max float",
+			modify:   func(u *validateUser) { u.Score = 2 },
+			expected: gsk.FieldError{Field: "score", Rule: "max", Param: "1.5", Message: "must be at most 1.5"},
+		},
+		{
+			name:     "max items",
+			modify:   func(u *validateUser) { u.Tags = []string{"a", "b", "c"} },
+			expected: gsk.FieldError{Field: "tags", Rule: "max", Param: "2", Message: "must be at most 2 items"},
+		},
+		{
+			name:     "pointer value",
+			modify:   func(u *validateUser) { u.Nickname = &short },
+			expected: gsk.FieldError{Field: "nickname", Rule: "min", Param: "3", Message: "must be at least 3 characters"},
+		},
+		{
+			name:     "nested struct",
+			modify:   func(u *validateUser) { u.Address.City = "" },
+			expected: gsk.FieldError{Field: "address.city", Rule: "required", Message: "is required"},
+		},
+		{
+			name:     "slice of structs",
+			modify:   func(u *validateUser) { u.Previous = append(u.Previous, validateAddress{City: "Goa", Zip: "1"}) },
+			expected: gsk.FieldError{Field: "previous[1].zip", Rule: "len", Param: "5", Message: "must be exactly 5 characters"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verrs := invalid(tc.modify)
+			assert.Equal(t, gsk.ValidationErrors{tc.expected}, verrs)
+		})
+	}
+
+	t.Run("returns error for unknown rules", func(t *testing.T) {
+		type unknownRule struct {
+			Name string `validate:"shiny"`
+		}
+
+		err := gsk.Validate(unknownRule{Name: "x"})
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, gsk.ErrValidation))
+	})
+
+	t.Run("returns error for non struct", func(t *testing.T) {
+		assert.Error(t, gsk.Validate("string"))
+	})
+}
@@ -0,0 +1,66 @@
+package gsk
+
+import (
+	"regexp"
+	"strings"
+)
+
+// versionPattern matches the version part of a vendor media type, eg: v2
+var versionPattern = regexp.MustCompile(`^v\d+$`)
+
+// Versioned dispatches the request to the handler of the API version requested in the Accept header
+// the version is the last part of a vendor media type, eg: application/vnd.app.v2+json requests v2
+// vendor media types without a version, eg: application/vnd.ms-excel, are ignored
+// requests without a version use the default version, unknown versions get a 406 Not Acceptable response
+// the resolved version is available with c.APIVersion
+// usage example:
+//
+//	server.Get("/users/:id", gsk.Versioned(map[string]gsk.HandlerFunc{
+//		"v1": handler.GetUserV1,
+//		"v2": handler.GetUserV2,
+//	}, "v1"))
+func Versioned(handlers map[string]HandlerFunc, defaultVersion string) HandlerFunc {
+	return func(c *Context) {
+		c.Writer.Header().Add("Vary", "Accept")
+
+		version := acceptVersion(c.Request.Header.Get("Accept"))
+		if version == "" {
+			version = defaultVersion
+		}
+
+		handler, ok := handlers[version]
+		if !ok {
+			c.ErrorResponse(ErrNotAcceptable)
+			return
+		}
+
+		c.apiVersion = version
+		handler(c)
+	}
+}
+
+// APIVersion returns the API version of the request resolved by Versioned
+func (c *Context) APIVersion() string {
+	return c.apiVersion
+}
+
+// acceptVersion returns the version of the first vendor media type in the Accept header
+// eg: application/vnd.app.v2+json -> v2, vendor media types without a version are skipped
+func acceptVersion(accept string) string {
+	for _, r := range parseAccept(accept) {
+		if r.quality <= 0 {
+			continue
+		}
+
+		_, subtype, _ := strings.Cut(r.mediaType, "/")
+		if !strings.HasPrefix(subtype, "vnd.") {
+			continue
+		}
+
+		subtype, _, _ = strings.Cut(subtype, "+")
+		if i := strings.LastIndex(subtype, "."); i > len("vnd.")-1 && versionPattern.MatchString(subtype[i+1:]) {
+			return subtype[i+1:]
+		}
+	}
+	return ""
+}
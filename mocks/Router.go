@@ -18,11 +18,26 @@ type Router struct {
 	mock.Mock
 }
 
+// GlobalOPTIONS provides a mock function with given fields: handler
+func (_m *Router) GlobalOPTIONS(handler http.HandlerFunc) {
+	_m.Called(handler)
+}
+
 // HandlerFunc provides a mock function with given fields: method, path, handler
 func (_m *Router) HandlerFunc(method string, path string, handler http.HandlerFunc) {
 	_m.Called(method, path, handler)
 }
 
+// MethodNotAllowed provides a mock function with given fields: handler
+func (_m *Router) MethodNotAllowed(handler http.HandlerFunc) {
+	_m.Called(handler)
+}
+
+// NotFound provides a mock function with given fields: handler
+func (_m *Router) NotFound(handler http.HandlerFunc) {
+	_m.Called(handler)
+}
+
 // ParamsFromContext provides a mock function with given fields: _a0
 func (_m *Router) ParamsFromContext(_a0 context.Context) gsk.Params {
 	ret := _m.Called(_a0)
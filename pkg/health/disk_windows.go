@@ -0,0 +1,25 @@
+//go:build windows
+
+package health
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var getDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// diskFree returns the bytes available to the user on the volume of the path
+func diskFree(path string) (uint64, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var freeBytesAvailable uint64
+	ret, _, err := getDiskFreeSpaceEx.Call(uintptr(unsafe.Pointer(pathPtr)), uintptr(unsafe.Pointer(&freeBytesAvailable)), 0, 0)
+	if ret == 0 {
+		return 0, err
+	}
+	return freeBytesAvailable, nil
+}
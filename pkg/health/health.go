@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrDiskSpaceLow = errors.New("disk space low")
+
+// Pinger is implemented by the database connections, eg: *pgx.Conn and *pgxpool.Pool from db.GetPGConnection and db.GetPGPool
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// SQL checks the database connection, eg: from db.GetSqliteConnection or db.GetMysqlConnection
+func SQL(conn *sql.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return conn.PingContext(ctx)
+	}
+}
+
+// Ping checks the connection with its Ping method
+func Ping(conn Pinger) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return conn.Ping(ctx)
+	}
+}
+
+// DiskSpace checks that the file system of the path has at least minFree bytes available
+func DiskSpace(path string, minFree uint64) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		free, err := diskFree(path)
+		if err != nil {
+			return err
+		}
+		if free < minFree {
+			return fmt.Errorf("%w: %d bytes free, %d required", ErrDiskSpaceLow, free, minFree)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,48 @@
+package health_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/adharshmk96/stk/pkg/health"
+	"github.com/stretchr/testify/assert"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type pinger struct {
+	err error
+}
+
+func (p pinger) Ping(ctx context.Context) error {
+	return p.err
+}
+
+func TestSQL(t *testing.T) {
+	conn, err := sql.Open("sqlite3", ":memory:")
+	assert.NoError(t, err)
+
+	check := health.SQL(conn)
+	assert.NoError(t, check(context.Background()))
+
+	conn.Close()
+	assert.Error(t, check(context.Background()))
+}
+
+func TestPing(t *testing.T) {
+	assert.NoError(t, health.Ping(pinger{})(context.Background()))
+
+	pingErr := errors.New("connection refused")
+	assert.ErrorIs(t, health.Ping(pinger{err: pingErr})(context.Background()), pingErr)
+}
+
+func TestDiskSpace(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, health.DiskSpace(dir, 1)(context.Background()))
+	assert.ErrorIs(t, health.DiskSpace(dir, math.MaxUint64)(context.Background()), health.ErrDiskSpaceLow)
+	assert.Error(t, health.DiskSpace(dir+"/missing", 1)(context.Background()))
+}
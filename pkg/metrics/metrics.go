@@ -0,0 +1,234 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBuckets are the histogram buckets for request latencies in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type metricType string
+
+const (
+	counterType   metricType = "counter"
+	gaugeType     metricType = "gauge"
+	histogramType metricType = "histogram"
+)
+
+// family is a metric with all its label combinations
+type family struct {
+	name       string
+	help       string
+	metricType metricType
+	labelNames []string
+	buckets    []float64
+
+	mux    sync.RWMutex
+	series map[string]*series
+}
+
+// series is a metric for a combination of label values
+type series struct {
+	labelValues []string
+
+	// counter and gauge value, float64 bits
+	value uint64
+
+	// histogram
+	mux          sync.Mutex
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+func newFamily(name string, help string, metricType metricType, buckets []float64, labelNames []string) *family {
+	return &family{
+		name:       name,
+		help:       help,
+		metricType: metricType,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     map[string]*series{},
+	}
+}
+
+// with returns the series of the label values, creating it if needed
+func (f *family) with(labelValues []string) *series {
+	if len(labelValues) != len(f.labelNames) {
+		panic("metrics: " + f.name + " expects labels " + strings.Join(f.labelNames, ", "))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	f.mux.RLock()
+	s, ok := f.series[key]
+	f.mux.RUnlock()
+	if ok {
+		return s
+	}
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if s, ok := f.series[key]; ok {
+		return s
+	}
+
+	s = &series{labelValues: append([]string(nil), labelValues...)}
+	if f.metricType == histogramType {
+		s.bucketCounts = make([]uint64, len(f.buckets))
+	}
+	f.series[key] = s
+	return s
+}
+
+// sortedSeries returns the series sorted by the label values
+func (f *family) sortedSeries() []*series {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+
+	result := make([]*series, 0, len(f.series))
+	for _, s := range f.series {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].labelValues, result[j].labelValues
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	return result
+}
+
+func (s *series) add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&s.value)
+		updated := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&s.value, old, updated) {
+			return
+		}
+	}
+}
+
+func (s *series) set(value float64) {
+	atomic.StoreUint64(&s.value, math.Float64bits(value))
+}
+
+func (s *series) get() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&s.value))
+}
+
+// CounterVec is a counter partitioned by labels, eg: requests by method and status
+type CounterVec struct {
+	family *family
+}
+
+// With returns the counter for the label values, in the order of the label names
+func (v *CounterVec) With(labelValues ...string) *Counter {
+	return &Counter{series: v.family.with(labelValues)}
+}
+
+// Counter is a value which only increases
+type Counter struct {
+	series *series
+}
+
+func (c *Counter) Inc() {
+	c.series.add(1)
+}
+
+// Add adds the value to the counter, negative values are ignored
+func (c *Counter) Add(value float64) {
+	if value < 0 {
+		return
+	}
+	c.series.add(value)
+}
+
+func (c *Counter) Value() float64 {
+	return c.series.get()
+}
+
+// GaugeVec is a gauge partitioned by labels
+type GaugeVec struct {
+	family *family
+}
+
+// With returns the gauge for the label values, in the order of the label names
+func (v *GaugeVec) With(labelValues ...string) *Gauge {
+	return &Gauge{series: v.family.with(labelValues)}
+}
+
+// Gauge is a value which can go up and down, eg: requests in flight
+type Gauge struct {
+	series *series
+}
+
+func (g *Gauge) Inc() {
+	g.series.add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.series.add(-1)
+}
+
+func (g *Gauge) Add(value float64) {
+	g.series.add(value)
+}
+
+func (g *Gauge) Set(value float64) {
+	g.series.set(value)
+}
+
+func (g *Gauge) Value() float64 {
+	return g.series.get()
+}
+
+// HistogramVec is a histogram partitioned by labels
+type HistogramVec struct {
+	family *family
+}
+
+// With returns the histogram for the label values, in the order of the label names
+func (v *HistogramVec) With(labelValues ...string) *Histogram {
+	return &Histogram{series: v.family.with(labelValues), buckets: v.family.buckets}
+}
+
+// Histogram counts the observed values in buckets, eg: request latencies
+type Histogram struct {
+	series  *series
+	buckets []float64
+}
+
+func (h *Histogram) Observe(value float64) {
+	h.series.mux.Lock()
+	defer h.series.mux.Unlock()
+
+	for i, upperBound := range h.buckets {
+		if value <= upperBound {
+			h.series.bucketCounts[i]++
+		}
+	}
+	h.series.sum += value
+	h.series.count++
+}
+
+// Count returns the number of observed values
+func (h *Histogram) Count() uint64 {
+	h.series.mux.Lock()
+	defer h.series.mux.Unlock()
+	return h.series.count
+}
+
+// Sum returns the sum of the observed values
+func (h *Histogram) Sum() float64 {
+	h.series.mux.Lock()
+	defer h.series.mux.Unlock()
+	return h.series.sum
+}
@@ -0,0 +1,207 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the content type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var namePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// DefaultRegistry is used by the Metrics middleware and the metrics handler when no registry is given
+var DefaultRegistry = NewRegistry()
+
+// Registry holds the metrics and writes them in the Prometheus text exposition format
+type Registry struct {
+	mux      sync.RWMutex
+	families map[string]*family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		families: map[string]*family{},
+	}
+}
+
+// Counter registers a counter, registering the same counter again returns the existing one
+// usage example:
+// requests := registry.Counter("jobs_processed_total", "Number of processed jobs.", "queue")
+// requests.With("emails").Inc()
+func (r *Registry) Counter(name string, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{family: r.register(name, help, counterType, nil, labelNames)}
+}
+
+// Gauge registers a gauge, registering the same gauge again returns the existing one
+func (r *Registry) Gauge(name string, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{family: r.register(name, help, gaugeType, nil, labelNames)}
+}
+
+// Histogram registers a histogram with the bucket upper bounds, DefaultBuckets if nil
+// registering the same histogram again returns the existing one, it panics when the buckets are different
+func (r *Registry) Histogram(name string, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &HistogramVec{family: r.register(name, help, histogramType, buckets, labelNames)}
+}
+
+// register panics for invalid names and when the name is registered with a different type, labels or buckets
+func (r *Registry) register(name string, help string, metricType metricType, buckets []float64, labelNames []string) *family {
+	if !namePattern.MatchString(name) {
+		panic("metrics: invalid metric name " + strconv.Quote(name))
+	}
+	for _, label := range labelNames {
+		if !namePattern.MatchString(label) || strings.Contains(label, ":") || label == "le" {
+			panic("metrics: invalid label name " + strconv.Quote(label))
+		}
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if existing, ok := r.families[name]; ok {
+		if existing.metricType != metricType || strings.Join(existing.labelNames, ",") != strings.Join(labelNames, ",") {
+			panic("metrics: " + name + " is already registered with a different type or labels")
+		}
+		if !equalBuckets(existing.buckets, buckets) {
+			panic("metrics: " + name + " is already registered with different buckets")
+		}
+		return existing
+	}
+
+	f := newFamily(name, help, metricType, buckets, append([]string(nil), labelNames...))
+	r.families[name] = f
+	return f
+}
+
+func equalBuckets(a []float64, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteTo writes the metrics sorted by name in the Prometheus text exposition format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mux.RLock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.mux.RUnlock()
+
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name < families[j].name
+	})
+
+	cw := &countingWriter{writer: bufio.NewWriter(w)}
+	for _, f := range families {
+		writeFamily(cw, f)
+	}
+	if cw.err != nil {
+		return cw.count, cw.err
+	}
+	return cw.count, cw.writer.Flush()
+}
+
+func writeFamily(w *countingWriter, f *family) {
+	if f.help != "" {
+		w.write("# HELP " + f.name + " " + escapeHelp(f.help) + "\n")
+	}
+	w.write("# TYPE " + f.name + " " + string(f.metricType) + "\n")
+
+	for _, s := range f.sortedSeries() {
+		if f.metricType != histogramType {
+			w.write(f.name + formatLabels(f.labelNames, s.labelValues, "", "") + " " + formatValue(s.get()) + "\n")
+			continue
+		}
+
+		s.mux.Lock()
+		counts := append([]uint64(nil), s.bucketCounts...)
+		sum, count := s.sum, s.count
+		s.mux.Unlock()
+
+		for i, upperBound := range f.buckets {
+			labels := formatLabels(f.labelNames, s.labelValues, "le", formatValue(upperBound))
+			w.write(f.name + "_bucket" + labels + " " + strconv.FormatUint(counts[i], 10) + "\n")
+		}
+		labels := formatLabels(f.labelNames, s.labelValues, "le", "+Inf")
+		w.write(f.name + "_bucket" + labels + " " + strconv.FormatUint(count, 10) + "\n")
+
+		labels = formatLabels(f.labelNames, s.labelValues, "", "")
+		w.write(f.name + "_sum" + labels + " " + formatValue(sum) + "\n")
+		w.write(f.name + "_count" + labels + " " + strconv.FormatUint(count, 10) + "\n")
+	}
+}
+
+// formatLabels formats the labels as {name="value",...}, with an extra label if given
+func formatLabels(names []string, values []string, extraName string, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		pairs = append(pairs, name+`="`+escapeLabelValue(values[i])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+// countingWriter counts the written bytes and keeps the first error
+type countingWriter struct {
+	writer *bufio.Writer
+	count  int64
+	err    error
+}
+
+func (cw *countingWriter) write(s string) {
+	if cw.err != nil {
+		return
+	}
+	n, err := cw.writer.WriteString(s)
+	cw.count += int64(n)
+	cw.err = err
+}
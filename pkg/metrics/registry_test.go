@@ -0,0 +1,102 @@
+package metrics_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/adharshmk96/stk/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("writes metrics in text exposition format", func(t *testing.T) {
+		registry := metrics.NewRegistry()
+
+		jobs := registry.Counter("jobs_total", "Processed jobs.\nBy queue.", "queue")
+		jobs.With("emails").Inc()
+		jobs.With("emails").Add(2)
+		jobs.With("emails").Add(-1)
+		jobs.With(`say "hi"\`).Inc()
+
+		workers := registry.Gauge("workers", "Busy workers.")
+		workers.With().Set(3)
+		workers.With().Dec()
+
+		latency := registry.Histogram("job_seconds", "Job latency.", []float64{1, 0.5}, "queue")
+		latency.With("emails").Observe(0.2)
+		latency.With("emails").Observe(0.7)
+		latency.With("emails").Observe(3)
+
+		var buf bytes.Buffer
+		n, err := registry.WriteTo(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+
+		expected := `# HELP job_seconds Job latency.
+# TYPE job_seconds histogram
+job_seconds_bucket{queue="emails",le="0.5"} 1
+job_seconds_bucket{queue="emails",le="1"} 2
+job_seconds_bucket{queue="emails",le="+Inf"} 3
+job_seconds_sum{queue="emails"} 3.9
+job_seconds_count{queue="emails"} 3
+# HELP jobs_total Processed jobs.\nBy queue.
+# TYPE jobs_total counter
+jobs_total{queue="emails"} 3
+jobs_total{queue="say \"hi\"\\"} 1
+# HELP workers Busy workers.
+# TYPE workers gauge
+workers 2
+`
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("returns the registered metric for the same name", func(t *testing.T) {
+		registry := metrics.NewRegistry()
+
+		registry.Counter("requests_total", "", "method").With("GET").Inc()
+		counter := registry.Counter("requests_total", "", "method").With("GET")
+		counter.Inc()
+
+		assert.Equal(t, float64(2), counter.Value())
+	})
+
+	t.Run("panics for invalid registrations", func(t *testing.T) {
+		registry := metrics.NewRegistry()
+		registry.Counter("requests_total", "", "method")
+
+		assert.Panics(t, func() { registry.Gauge("requests_total", "", "method") })
+		assert.Panics(t, func() { registry.Counter("requests_total", "", "route") })
+		assert.Panics(t, func() { registry.Counter("requests-total", "") })
+		assert.Panics(t, func() { registry.Histogram("latency", "", nil, "le") })
+		assert.Panics(t, func() { registry.Counter("requests_total", "", "method").With("GET", "extra") })
+
+		registry.Histogram("latency_seconds", "", []float64{0.1, 1}, "method")
+		assert.NotPanics(t, func() { registry.Histogram("latency_seconds", "", []float64{1, 0.1}, "method") })
+		assert.Panics(t, func() { registry.Histogram("latency_seconds", "", []float64{0.5, 1}, "method") })
+		assert.Panics(t, func() { registry.Histogram("latency_seconds", "", nil, "method") })
+	})
+
+	t.Run("records concurrently", func(t *testing.T) {
+		registry := metrics.NewRegistry()
+		counter := registry.Counter("requests_total", "", "method")
+		histogram := registry.Histogram("latency_seconds", "", nil)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					counter.With("GET").Inc()
+					histogram.With().Observe(0.1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, float64(5000), counter.With("GET").Value())
+		assert.Equal(t, uint64(5000), histogram.With().Count())
+		assert.InDelta(t, 500, histogram.With().Sum(), 0.0001)
+	})
+}
@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adharshmk96/stk/gsk"
+)
+
+const defaultCacheTTL = time.Minute
+
+type CacheConfig struct {
+	// CacheControl is set as the Cache-Control header of the responses, eg: public, max-age=300
+	// the header set by the handler is kept, no header is set if empty
+	CacheControl string
+	// Store keeps the full responses to answer repeated requests without the handler, eg: NewLRUCacheStore(1000)
+	// responses are not stored if nil
+	Store CacheStore
+	// TTL is the time the responses are kept in the store, default 1 minute
+	TTL time.Duration
+}
+
+// Cache adds ETags to the responses of GET and HEAD requests and answers conditional requests
+// the ETag is computed over the buffered response body when the handler did not set one,
+// requests with a matching If-None-Match header get a 304 Not Modified response
+// with a Store, successful responses are stored by method, url and the request headers in their Vary header,
+// responses with Set-Cookie or Cache-Control no-store or private are not stored. Responses to requests with
+// an Authorization header are stored and served only when marked public, s-maxage or must-revalidate.
+// The store is shared by all the users: responses depending on a cookie, eg: a session, must set
+// Cache-Control private or Vary: Cookie, otherwise they are served to other users
+// usage example:
+//
+//	server.Get("/products", handler.ListProducts, middleware.Cache(middleware.CacheConfig{
+//		CacheControl: "public, max-age=300",
+//		Store:        middleware.NewLRUCacheStore(1000),
+//	}))
+func Cache(config ...CacheConfig) gsk.Middleware {
+	var cacheConfig CacheConfig
+	if len(config) > 0 {
+		cacheConfig = config[0]
+	}
+	if cacheConfig.TTL <= 0 {
+		cacheConfig.TTL = defaultCacheTTL
+	}
+
+	return func(next gsk.HandlerFunc) gsk.HandlerFunc {
+		return func(c *gsk.Context) {
+			method := c.Request.Method
+			if method != http.MethodGet && method != http.MethodHead {
+				next(c)
+				return
+			}
+
+			key := method + " " + c.Request.Host + c.Request.URL.RequestURI()
+			authorized := c.Request.Header.Get("Authorization") != ""
+			if cacheConfig.Store != nil {
+				cached, ok := lookupResponse(cacheConfig.Store, key, c.Request)
+				if ok && (!authorized || isSharedWithAuthorization(cached.Header)) {
+					writeCachedResponse(c, cached)
+					return
+				}
+			}
+
+			next(c)
+
+			// streamed responses are already sent, eg: large files
+			status := c.GetStatusCode()
+			if c.ResponseWritten() || status != 0 && status != http.StatusOK {
+				return
+			}
+
+			headers := c.Writer.Header()
+			if headers.Get("ETag") == "" {
+				headers.Set("ETag", bodyETag(c.GetResponseBody()))
+			}
+			if cacheConfig.CacheControl != "" && headers.Get("Cache-Control") == "" {
+				headers.Set("Cache-Control", cacheConfig.CacheControl)
+			}
+
+			if cacheConfig.Store != nil && isStorable(headers) && (!authorized || isSharedWithAuthorization(headers)) {
+				storeResponse(cacheConfig.Store, key, c.Request, &CachedResponse{
+					Status:   http.StatusOK,
+					Header:   headers.Clone(),
+					Body:     c.GetResponseBody(),
+					StoredAt: time.Now(),
+				}, cacheConfig.TTL)
+			}
+
+			if etagMatches(c.Request.Header.Get("If-None-Match"), headers.Get("ETag")) {
+				notModified(c)
+			}
+		}
+	}
+}
+
+// writeCachedResponse writes the stored response, or 304 if the ETag matches the request
+// headers already set for this request, eg: by the RequestID middleware, are kept
+func writeCachedResponse(c *gsk.Context, cached *CachedResponse) {
+	headers := c.Writer.Header()
+	for name, values := range cached.Header {
+		if _, ok := headers[name]; !ok {
+			headers[name] = append([]string(nil), values...)
+		}
+	}
+	headers.Set("Age", strconv.Itoa(int(time.Since(cached.StoredAt).Seconds())))
+
+	if etagMatches(c.Request.Header.Get("If-None-Match"), headers.Get("ETag")) {
+		notModified(c)
+		return
+	}
+	c.Status(cached.Status).RawResponse(cached.Body)
+}
+
+// notModified replaces the response with 304 Not Modified, keeping the caching headers
+func notModified(c *gsk.Context) {
+	headers := c.Writer.Header()
+	headers.Del("Content-Type")
+	headers.Del("Content-Length")
+	c.Status(http.StatusNotModified).RawResponse(nil)
+}
+
+// lookupResponse returns the response stored for the request
+func lookupResponse(store CacheStore, key string, r *http.Request) (*CachedResponse, bool) {
+	cached, ok := store.Get(key)
+	if !ok || len(cached.Vary) == 0 {
+		return cached, ok
+	}
+	return store.Get(varyKey(key, cached.Vary, r))
+}
+
+// storeResponse stores the response, responses varying by request headers are stored for each value of the headers
+func storeResponse(store CacheStore, key string, r *http.Request, response *CachedResponse, ttl time.Duration) {
+	vary := varyHeaders(response.Header)
+	if len(vary) == 0 {
+		store.Set(key, response, ttl)
+		return
+	}
+
+	store.Set(key, &CachedResponse{Vary: vary, StoredAt: response.StoredAt}, ttl)
+	store.Set(varyKey(key, vary, r), response, ttl)
+}
+
+// varyKey adds the values of the request headers to the key
+func varyKey(key string, vary []string, r *http.Request) string {
+	var b strings.Builder
+	b.WriteString(key)
+	for _, name := range vary {
+		b.WriteString("\n" + name + ":" + strings.Join(r.Header.Values(name), ","))
+	}
+	return b.String()
+}
+
+// varyHeaders returns the canonical header names of the Vary header
+func varyHeaders(headers http.Header) []string {
+	var vary []string
+	for _, value := range headers.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				vary = append(vary, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return vary
+}
+
+// isStorable reports whether a shared cache may store the response
+func isStorable(headers http.Header) bool {
+	if headers.Get("Set-Cookie") != "" {
+		return false
+	}
+	for _, name := range varyHeaders(headers) {
+		if name == "*" {
+			return false
+		}
+	}
+
+	directives := cacheControlDirectives(headers)
+	_, noStore := directives["no-store"]
+	_, private := directives["private"]
+	return !noStore && !private
+}
+
+// isSharedWithAuthorization reports whether the response to a request with an Authorization header
+// may be served from a shared cache, see RFC 9111 section 3.5
+func isSharedWithAuthorization(headers http.Header) bool {
+	directives := cacheControlDirectives(headers)
+	for _, name := range []string{"public", "s-maxage", "must-revalidate"} {
+		if _, ok := directives[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheControlDirectives parses the Cache-Control header into the lower case directive names and their values
+// eg: public, max-age=300 -> {"public": "", "max-age": "300"}
+func cacheControlDirectives(headers http.Header) map[string]string {
+	directives := map[string]string{}
+	for _, value := range headers.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, argument, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				directives[name] = strings.Trim(strings.TrimSpace(argument), `"`)
+			}
+		}
+	}
+	return directives
+}
+
+// bodyETag returns a strong ETag from the hash and size of the body
+func bodyETag(body []byte) string {
+	hash := fnv.New64a()
+	hash.Write(body)
+	return fmt.Sprintf(`"%x-%x"`, hash.Sum64(), len(body))
+}
+
+// etagMatches compares the If-None-Match header with the ETag, weak ETags match their strong version
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" || etag == "" {
+		return false
+	}
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
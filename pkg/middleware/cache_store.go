@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultCacheCapacity = 1000
+
+// CachedResponse is a response stored by the Cache middleware
+type CachedResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	// StoredAt is the time the response was stored, used for the Age header
+	StoredAt time.Time
+	// Vary are the request headers the response depends on, an entry with Vary and no response
+	// points to the responses stored for each value of the headers
+	Vary []string
+}
+
+// CacheStore stores the responses of the Cache middleware, it must be safe for concurrent use
+type CacheStore interface {
+	// Get returns the response stored for the key, false if it is missing or expired
+	Get(key string) (*CachedResponse, bool)
+	// Set stores the response for the key for the ttl
+	Set(key string, response *CachedResponse, ttl time.Duration)
+}
+
+// LRUCacheStore is an in-memory CacheStore, the least recently used responses are removed when it is full
+type LRUCacheStore struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+type lruEntry struct {
+	key      string
+	response *CachedResponse
+	expires  time.Time
+}
+
+// NewLRUCacheStore creates an in-memory store for the number of responses, default 1000
+func NewLRUCacheStore(capacity int) *LRUCacheStore {
+	if capacity <= 0 {
+		capacity = defaultCacheCapacity
+	}
+	return &LRUCacheStore{
+		capacity: capacity,
+		items:    map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (s *LRUCacheStore) Get(key string) (*CachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		s.remove(element)
+		return nil, false
+	}
+
+	s.order.MoveToFront(element)
+	return entry.response, true
+}
+
+func (s *LRUCacheStore) Set(key string, response *CachedResponse, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expires := time.Now().Add(ttl)
+	if element, ok := s.items[key]; ok {
+		element.Value = &lruEntry{key: key, response: response, expires: expires}
+		s.order.MoveToFront(element)
+		return
+	}
+
+	s.items[key] = s.order.PushFront(&lruEntry{key: key, response: response, expires: expires})
+	if s.order.Len() > s.capacity {
+		s.remove(s.order.Back())
+	}
+}
+
+// Len returns the number of stored responses, including the expired ones not removed yet
+func (s *LRUCacheStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+func (s *LRUCacheStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.items, element.Value.(*lruEntry).key)
+}
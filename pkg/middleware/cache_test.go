@@ -0,0 +1,245 @@
+package middleware_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/adharshmk96/stk/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("sets the ETag and answers If-None-Match with 304", func(t *testing.T) {
+		s := gsk.New()
+		s.Get("/products", func(c *gsk.Context) {
+			c.JSONResponse(gsk.Map{"products": []string{"book"}})
+		}, middleware.Cache(middleware.CacheConfig{CacheControl: "public, max-age=300"}))
+
+		rr, _ := s.Test("GET", "/products", nil)
+		etag := rr.Header().Get("ETag")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NotEmpty(t, etag)
+		assert.Equal(t, "public, max-age=300", rr.Header().Get("Cache-Control"))
+
+		rr, _ = s.Test("GET", "/products", nil, gsk.TestParams{Headers: map[string]string{"If-None-Match": etag}})
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+		assert.Empty(t, rr.Body.String())
+		assert.Equal(t, etag, rr.Header().Get("ETag"))
+		assert.Equal(t, "public, max-age=300", rr.Header().Get("Cache-Control"))
+
+		rr, _ = s.Test("GET", "/products", nil, gsk.TestParams{Headers: map[string]string{"If-None-Match": `"other", W/` + etag}})
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+
+		rr, _ = s.Test("GET", "/products", nil, gsk.TestParams{Headers: map[string]string{"If-None-Match": `"other"`}})
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("keeps the headers set by the handler", func(t *testing.T) {
+		s := gsk.New()
+		s.Use(middleware.Cache(middleware.CacheConfig{CacheControl: "public, max-age=300"}))
+		s.Get("/", func(c *gsk.Context) {
+			c.SetHeader("ETag", `"v1"`)
+			c.SetHeader("Cache-Control", "no-cache")
+			c.StringResponse("hello")
+		})
+
+		rr, _ := s.Test("GET", "/", nil)
+		assert.Equal(t, `"v1"`, rr.Header().Get("ETag"))
+		assert.Equal(t, "no-cache", rr.Header().Get("Cache-Control"))
+	})
+
+	t.Run("skips other methods and statuses", func(t *testing.T) {
+		s := gsk.New()
+		s.Use(middleware.Cache())
+		s.Post("/", func(c *gsk.Context) {
+			c.StringResponse("created")
+		})
+		s.Get("/missing", func(c *gsk.Context) {
+			c.Status(http.StatusNotFound).StringResponse("not found")
+		})
+
+		rr, _ := s.Test("POST", "/", nil)
+		assert.Empty(t, rr.Header().Get("ETag"))
+
+		rr, _ = s.Test("GET", "/missing", nil)
+		assert.Empty(t, rr.Header().Get("ETag"))
+	})
+
+	t.Run("serves stored responses without the handler", func(t *testing.T) {
+		calls := 0
+		s := gsk.New()
+		s.Get("/products", func(c *gsk.Context) {
+			calls++
+			c.StringResponse(fmt.Sprintf("response %d", calls))
+		}, middleware.Cache(middleware.CacheConfig{Store: middleware.NewLRUCacheStore(10)}))
+
+		rr, _ := s.Test("GET", "/products?page=1", nil)
+		assert.Equal(t, "response 1", rr.Body.String())
+
+		rr, _ = s.Test("GET", "/products?page=1", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "response 1", rr.Body.String())
+		assert.Equal(t, "text/plain", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "0", rr.Header().Get("Age"))
+
+		rr, _ = s.Test("GET", "/products?page=1", nil, gsk.TestParams{Headers: map[string]string{"If-None-Match": rr.Header().Get("ETag")}})
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+
+		rr, _ = s.Test("GET", "/products?page=2", nil)
+		assert.Equal(t, "response 2", rr.Body.String())
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("stores the responses by the Vary headers", func(t *testing.T) {
+		calls := 0
+		s := gsk.New()
+		s.Get("/greeting", func(c *gsk.Context) {
+			calls++
+			c.SetHeader("Vary", "Accept-Language")
+			c.StringResponse("hello " + c.Request.Header.Get("Accept-Language"))
+		}, middleware.Cache(middleware.CacheConfig{Store: middleware.NewLRUCacheStore(10)}))
+
+		english := gsk.TestParams{Headers: map[string]string{"Accept-Language": "en"}}
+		french := gsk.TestParams{Headers: map[string]string{"Accept-Language": "fr"}}
+
+		rr, _ := s.Test("GET", "/greeting", nil, english)
+		assert.Equal(t, "hello en", rr.Body.String())
+		rr, _ = s.Test("GET", "/greeting", nil, french)
+		assert.Equal(t, "hello fr", rr.Body.String())
+		rr, _ = s.Test("GET", "/greeting", nil, english)
+		assert.Equal(t, "hello en", rr.Body.String())
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("does not store private responses", func(t *testing.T) {
+		calls := 0
+		s := gsk.New()
+		s.Get("/me", func(c *gsk.Context) {
+			calls++
+			c.SetHeader("Cache-Control", "private, max-age=60")
+			c.StringResponse("me")
+		}, middleware.Cache(middleware.CacheConfig{Store: middleware.NewLRUCacheStore(10)}))
+
+		s.Test("GET", "/me", nil)
+		s.Test("GET", "/me", nil)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("parses the Cache-Control directives", func(t *testing.T) {
+		calls := 0
+		s := gsk.New()
+		s.Get("/", func(c *gsk.Context) {
+			calls++
+			c.SetHeader("Cache-Control", "max-age=60, x-private-ttl=10")
+			c.StringResponse("hello")
+		}, middleware.Cache(middleware.CacheConfig{Store: middleware.NewLRUCacheStore(10)}))
+
+		s.Test("GET", "/", nil)
+		s.Test("GET", "/", nil)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("shares responses to authorized requests only when allowed", func(t *testing.T) {
+		calls := 0
+		s := gsk.New()
+		s.Get("/me", func(c *gsk.Context) {
+			calls++
+			c.StringResponse(c.Request.Header.Get("Authorization"))
+		}, middleware.Cache(middleware.CacheConfig{Store: middleware.NewLRUCacheStore(10)}))
+		s.Get("/catalog", func(c *gsk.Context) {
+			calls++
+			c.SetHeader("Cache-Control", "public, max-age=60")
+			c.StringResponse("catalog")
+		}, middleware.Cache(middleware.CacheConfig{Store: middleware.NewLRUCacheStore(10)}))
+
+		alice := gsk.TestParams{Headers: map[string]string{"Authorization": "Bearer alice"}}
+		bob := gsk.TestParams{Headers: map[string]string{"Authorization": "Bearer bob"}}
+
+		rr, _ := s.Test("GET", "/me", nil, alice)
+		assert.Equal(t, "Bearer alice", rr.Body.String())
+		rr, _ = s.Test("GET", "/me", nil, bob)
+		assert.Equal(t, "Bearer bob", rr.Body.String())
+		assert.Equal(t, 2, calls)
+
+		// a response stored for an anonymous request is not served to an authorized one
+		s.Test("GET", "/me", nil)
+		rr, _ = s.Test("GET", "/me", nil, bob)
+		assert.Equal(t, "Bearer bob", rr.Body.String())
+		assert.Equal(t, 4, calls)
+
+		s.Test("GET", "/catalog", nil, alice)
+		rr, _ = s.Test("GET", "/catalog", nil, bob)
+		assert.Equal(t, "catalog", rr.Body.String())
+		assert.Equal(t, 5, calls)
+	})
+
+	t.Run("does not store streamed responses", func(t *testing.T) {
+		dir := t.TempDir()
+		filePath := filepath.Join(dir, "report.txt")
+		assert.NoError(t, os.WriteFile(filePath, []byte("quarterly report"), 0o644))
+
+		calls := 0
+		s := gsk.New(&gsk.ServerConfig{FileBufferLimit: 4})
+		s.Get("/report", func(c *gsk.Context) {
+			calls++
+			c.File(filePath)
+		}, middleware.Cache(middleware.CacheConfig{Store: middleware.NewLRUCacheStore(10)}))
+
+		s.Test("GET", "/report", nil)
+		rr, _ := s.Test("GET", "/report", nil)
+		assert.Equal(t, "quarterly report", rr.Body.String())
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("expires the stored responses", func(t *testing.T) {
+		calls := 0
+		s := gsk.New()
+		s.Get("/", func(c *gsk.Context) {
+			calls++
+			c.StringResponse("hello")
+		}, middleware.Cache(middleware.CacheConfig{Store: middleware.NewLRUCacheStore(10), TTL: 10 * time.Millisecond}))
+
+		s.Test("GET", "/", nil)
+		s.Test("GET", "/", nil)
+		assert.Equal(t, 1, calls)
+
+		time.Sleep(20 * time.Millisecond)
+		s.Test("GET", "/", nil)
+		assert.Equal(t, 2, calls)
+	})
+}
+
+func TestLRUCacheStore(t *testing.T) {
+	store := middleware.NewLRUCacheStore(2)
+	response := func(body string) *middleware.CachedResponse {
+		return &middleware.CachedResponse{Status: http.StatusOK, Body: []byte(body)}
+	}
+
+	store.Set("a", response("a"), time.Minute)
+	store.Set("b", response("b"), time.Minute)
+
+	// a is used, so b is the least recently used
+	_, ok := store.Get("a")
+	assert.True(t, ok)
+
+	store.Set("c", response("c"), time.Minute)
+	assert.Equal(t, 2, store.Len())
+
+	_, ok = store.Get("b")
+	assert.False(t, ok)
+
+	cached, ok := store.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "a", string(cached.Body))
+
+	store.Set("a", response("updated"), time.Minute)
+	cached, _ = store.Get("a")
+	assert.Equal(t, "updated", string(cached.Body))
+	assert.Equal(t, 2, store.Len())
+}
@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/adharshmk96/stk/gsk"
+)
+
+const defaultCompressMinSize = 1024
+
+// DefaultCompressTypes are the content types compressed by default
+var DefaultCompressTypes = []string{
+	"text/*",
+	gsk.MIMEJSON,
+	gsk.MIMEProblemJSON,
+	"application/javascript",
+	gsk.MIMEXML,
+	gsk.MIMEYAML,
+	"image/svg+xml",
+}
+
+// CompressEncoder is a content coding used by the Compress middleware
+type CompressEncoder struct {
+	// Name is the coding in the Accept-Encoding and Content-Encoding headers, eg: gzip
+	Name string
+	// NewWriter returns a writer compressing into w
+	NewWriter func(w io.Writer) (io.WriteCloser, error)
+}
+
+// GzipEncoder compresses with gzip at the level, eg: gzip.BestSpeed
+func GzipEncoder(level int) CompressEncoder {
+	return CompressEncoder{
+		Name: "gzip",
+		NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriterLevel(w, level)
+		},
+	}
+}
+
+// DeflateEncoder compresses with deflate at the level, eg: flate.BestSpeed
+func DeflateEncoder(level int) CompressEncoder {
+	return CompressEncoder{
+		Name: "deflate",
+		NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(w, level)
+		},
+	}
+}
+
+type CompressConfig struct {
+	// MinSize is the minimum size of the response body in bytes to be compressed, default 1024
+	MinSize int
+	// ContentTypes are the compressed content types, eg: text/* or application/json, default DefaultCompressTypes
+	ContentTypes []string
+	// Encoders are the supported codings in the order of preference, default gzip and deflate
+	// other codings can be added, eg: brotli
+	Encoders []CompressEncoder
+}
+
+// Compress compresses the buffered response body with the coding negotiated from the Accept-Encoding header
+// the body is compressed once after the handler, responses which are small, already encoded,
+// partial or marked with Cache-Control no-transform are sent as they are
+// usage example:
+//
+//	server.Use(middleware.Compress())
+//	server.Use(middleware.Compress(middleware.CompressConfig{
+//		Encoders: []middleware.CompressEncoder{brotliEncoder, middleware.GzipEncoder(gzip.DefaultCompression)},
+//	}))
+func Compress(config ...CompressConfig) gsk.Middleware {
+	var compressConfig CompressConfig
+	if len(config) > 0 {
+		compressConfig = config[0]
+	}
+	if compressConfig.MinSize <= 0 {
+		compressConfig.MinSize = defaultCompressMinSize
+	}
+	if len(compressConfig.ContentTypes) == 0 {
+		compressConfig.ContentTypes = DefaultCompressTypes
+	}
+	if len(compressConfig.Encoders) == 0 {
+		compressConfig.Encoders = []CompressEncoder{
+			GzipEncoder(gzip.DefaultCompression),
+			DeflateEncoder(flate.DefaultCompression),
+		}
+	}
+
+	encoders := map[string]CompressEncoder{}
+	codings := make([]string, 0, len(compressConfig.Encoders))
+	for _, encoder := range compressConfig.Encoders {
+		encoders[encoder.Name] = encoder
+		codings = append(codings, encoder.Name)
+	}
+
+	return func(next gsk.HandlerFunc) gsk.HandlerFunc {
+		return func(c *gsk.Context) {
+			next(c)
+
+			// streamed responses are already sent, eg: large files
+			if c.ResponseWritten() {
+				return
+			}
+
+			headers := c.Writer.Header()
+			body := c.GetResponseBody()
+
+			contentType := headers.Get("Content-Type")
+			if contentType == "" {
+				contentType = http.DetectContentType(body)
+			}
+			if !isCompressibleType(contentType, compressConfig.ContentTypes) {
+				return
+			}
+			// the response depends on the Accept-Encoding header even when it is not compressed
+			addVary(headers, "Accept-Encoding")
+
+			if len(body) < compressConfig.MinSize || !isCompressible(c.GetStatusCode(), headers) {
+				return
+			}
+
+			coding := gsk.NegotiateEncoding(c.Request.Header.Get("Accept-Encoding"), codings)
+			if coding == "" {
+				return
+			}
+
+			compressed, err := compress(encoders[coding], body)
+			if err != nil {
+				c.Logger().Error("error compressing response", "encoding", coding, "error", err)
+				return
+			}
+			if len(compressed) >= len(body) {
+				return
+			}
+
+			// the content type can not be sniffed from the compressed body
+			headers.Set("Content-Type", contentType)
+			headers.Set("Content-Encoding", coding)
+			headers.Del("Content-Length")
+			// byte ranges of the original body do not apply to the compressed body
+			headers.Del("Accept-Ranges")
+			if etag := headers.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+				headers.Set("ETag", "W/"+etag)
+			}
+			c.RawResponse(compressed)
+		}
+	}
+}
+
+// isCompressible reports whether the status and headers allow changing the body
+func isCompressible(status int, headers http.Header) bool {
+	switch status {
+	case http.StatusNoContent, http.StatusPartialContent, http.StatusNotModified:
+		return false
+	}
+	if headers.Get("Content-Encoding") != "" || headers.Get("Content-Range") != "" {
+		return false
+	}
+	_, noTransform := cacheControlDirectives(headers)["no-transform"]
+	return !noTransform
+}
+
+// isCompressibleType reports whether the content type matches the types, eg: text/* or application/json
+func isCompressibleType(contentType string, types []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, t := range types {
+		if t == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func compress(encoder CompressEncoder, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer, err := encoder.NewWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// addVary adds the header to the Vary header if it is not listed
+func addVary(headers http.Header, header string) {
+	for _, value := range headers.Values("Vary") {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "*" || strings.EqualFold(field, header) {
+				return
+			}
+		}
+	}
+	headers.Add("Vary", header)
+}
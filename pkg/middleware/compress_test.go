@@ -0,0 +1,157 @@
+package middleware_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/adharshmk96/stk/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+var largeText = strings.Repeat("compressible response body ", 100)
+
+func gunzip(t *testing.T, body []byte) string {
+	t.Helper()
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	assert.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func acceptEncoding(value string) gsk.TestParams {
+	return gsk.TestParams{Headers: map[string]string{"Accept-Encoding": value}}
+}
+
+func TestCompress(t *testing.T) {
+	newServer := func(config ...middleware.CompressConfig) *gsk.Server {
+		s := gsk.New()
+		s.Use(middleware.Compress(config...))
+		s.Get("/text", func(c *gsk.Context) {
+			c.SetHeader("ETag", `"v1"`)
+			c.StringResponse(largeText)
+		})
+		s.Get("/small", func(c *gsk.Context) {
+			c.StringResponse("small")
+		})
+		s.Get("/image", func(c *gsk.Context) {
+			c.SetHeader("Content-Type", "image/png")
+			c.RawResponse([]byte(largeText))
+		})
+		s.Get("/no-transform", func(c *gsk.Context) {
+			c.SetHeader("Cache-Control", "no-transform")
+			c.StringResponse(largeText)
+		})
+		return s
+	}
+
+	t.Run("compresses with gzip", func(t *testing.T) {
+		rr, _ := newServer().Test("GET", "/text", nil, acceptEncoding("gzip, deflate"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+		assert.Equal(t, "text/plain", rr.Header().Get("Content-Type"))
+		assert.Equal(t, `W/"v1"`, rr.Header().Get("ETag"))
+		assert.Equal(t, largeText, gunzip(t, rr.Body.Bytes()))
+	})
+
+	t.Run("negotiates the coding by quality", func(t *testing.T) {
+		rr, _ := newServer().Test("GET", "/text", nil, acceptEncoding("gzip;q=0.5, deflate"))
+
+		assert.Equal(t, "deflate", rr.Header().Get("Content-Encoding"))
+		data, err := io.ReadAll(flate.NewReader(rr.Body))
+		assert.NoError(t, err)
+		assert.Equal(t, largeText, string(data))
+	})
+
+	t.Run("skips responses which can not be compressed", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			path           string
+			acceptEncoding string
+			vary           string
+		}{
+			{name: "no accepted coding", path: "/text", acceptEncoding: "", vary: "Accept-Encoding"},
+			{name: "identity only", path: "/text", acceptEncoding: "identity, *;q=0", vary: "Accept-Encoding"},
+			{name: "below the minimum size", path: "/small", acceptEncoding: "gzip", vary: "Accept-Encoding"},
+			{name: "content type not allowed", path: "/image", acceptEncoding: "gzip", vary: ""},
+			{name: "no-transform", path: "/no-transform", acceptEncoding: "gzip", vary: "Accept-Encoding"},
+		}
+
+		s := newServer()
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rr, _ := s.Test("GET", tt.path, nil, acceptEncoding(tt.acceptEncoding))
+
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Empty(t, rr.Header().Get("Content-Encoding"))
+				assert.Equal(t, tt.vary, rr.Header().Get("Vary"))
+			})
+		}
+	})
+
+	t.Run("compresses once when applied twice", func(t *testing.T) {
+		s := gsk.New()
+		s.Use(middleware.Compress())
+		s.Get("/text", func(c *gsk.Context) {
+			c.StringResponse(largeText)
+		}, middleware.Compress())
+
+		rr, _ := s.Test("GET", "/text", nil, acceptEncoding("gzip"))
+		assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, []string{"Accept-Encoding"}, rr.Header().Values("Vary"))
+		assert.Equal(t, largeText, gunzip(t, rr.Body.Bytes()))
+	})
+
+	t.Run("uses the configured encoders and threshold", func(t *testing.T) {
+		custom := middleware.CompressEncoder{
+			Name: "test",
+			NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+				return gzip.NewWriterLevel(w, gzip.BestSpeed)
+			},
+		}
+		s := newServer(middleware.CompressConfig{MinSize: 4, Encoders: []middleware.CompressEncoder{custom}})
+
+		rr, _ := s.Test("GET", "/text", nil, acceptEncoding("gzip, test"))
+		assert.Equal(t, "test", rr.Header().Get("Content-Encoding"))
+
+		rr, _ = s.Test("GET", "/small", nil, acceptEncoding("test"))
+		assert.Empty(t, rr.Header().Get("Content-Encoding"), "compressed body is larger than the original")
+	})
+
+	t.Run("compresses static files", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{
+			StaticFS: fstest.MapFS{
+				"app.css": &fstest.MapFile{Data: []byte(largeText)},
+			},
+			StaticMiddlewares: []gsk.Middleware{middleware.Compress()},
+		})
+
+		rr, _ := s.Test("GET", "/static/app.css", nil, acceptEncoding("gzip"))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, "text/css; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.Empty(t, rr.Header().Get("Accept-Ranges"))
+		assert.Equal(t, largeText, gunzip(t, rr.Body.Bytes()))
+	})
+
+	t.Run("compresses yaml responses", func(t *testing.T) {
+		s := gsk.New()
+		s.Use(middleware.Compress())
+		s.Get("/config", func(c *gsk.Context) {
+			c.YAMLResponse(gsk.Map{"description": largeText})
+		})
+
+		rr, _ := s.Test("GET", "/config", nil, acceptEncoding("gzip"))
+		assert.Equal(t, gsk.MIMEYAML, rr.Header().Get("Content-Type"))
+		assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	})
+}
@@ -2,7 +2,10 @@ package middleware
 
 import (
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/adharshmk96/stk/gsk"
 )
@@ -12,89 +15,312 @@ var (
 	// "POST, GET, OPTIONS, PUT, DELETE, PATCH"
 	defaultAllowHeaders = []string{"Accept", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization"}
 	// "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization"
-	defaultAllowCredentials = "true"
 )
 
 const (
-	defaultCORSOrigin = "same-origin"
-
 	AccessControlAllowOrigin      = "Access-Control-Allow-Origin"
 	AccessControlAllowMethods     = "Access-Control-Allow-Methods"
 	AccessControlAllowHeaders     = "Access-Control-Allow-Headers"
 	AccessControlAllowCredentials = "Access-Control-Allow-Credentials"
+	AccessControlExposeHeaders    = "Access-Control-Expose-Headers"
+	AccessControlMaxAge           = "Access-Control-Max-Age"
+	AccessControlRequestMethod    = "Access-Control-Request-Method"
+	AccessControlRequestHeaders   = "Access-Control-Request-Headers"
 )
 
 type CORSConfig struct {
+	// AllowedOrigins lists the origins allowed to make cross origin requests, eg: https://example.com
+	// a wildcard matches the subdomains, eg: https://*.example.com, and "*" matches any origin
+	// the "null" origin of sandboxed pages and local files is never allowed
 	AllowedOrigins []string
+	// AllowOriginFunc validates the origins not in AllowedOrigins, eg: to look up the origins of the tenants
+	AllowOriginFunc func(origin string) bool
+	// AllowedMethods are the methods allowed in preflight requests, default POST, GET, OPTIONS, PUT, DELETE, PATCH
 	AllowedMethods []string
+	// AllowedHeaders are the request headers allowed in preflight requests, "*" allows any header
+	// default Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization
 	AllowedHeaders []string
-	AllowAll       bool
+	// ExposedHeaders are the response headers readable by the scripts of the origin
+	ExposedHeaders []string
+	// AllowCredentials allows cookies and the Authorization header in cross origin requests
+	// it can not be used with AllowAll, the origins must be listed or validated by AllowOriginFunc
+	AllowCredentials bool
+	// MaxAge is the time the browsers cache the preflight responses, not sent when zero
+	MaxAge time.Duration
+	// AllowAll allows any origin, same as AllowedOrigins "*"
+	AllowAll bool
+}
+
+// corsPolicy is the CORSConfig prepared for matching the requests
+type corsPolicy struct {
+	allowAll         bool
+	origins          map[string]bool
+	patterns         []originPattern
+	allowOriginFunc  func(origin string) bool
+	methods          []string
+	headers          []string
+	anyHeader        bool
+	allowCredentials bool
+	allowedMethods   string
+	allowedHeaders   string
+	exposedHeaders   string
+	maxAge           string
 }
 
+// originPattern is an allowed origin with a wildcard, eg: https://*.example.com
+type originPattern struct {
+	prefix string
+	suffix string
+}
+
+// CORS handles the cross origin requests of the allowed origins
+// requests from other origins get a 403 Forbidden response, requests without an Origin header
+// and same origin requests are passed to the handler without the CORS headers, the scheme of the
+// request is read from X-Forwarded-Proto for trusted proxies, see gsk.Context.Scheme
+// preflight requests, OPTIONS requests with Access-Control-Request-Method, are answered with 204 No Content
+// by default, no cross origin request is allowed, the config panics if AllowAll is used with AllowCredentials
+// usage example:
+//
+//	server.Use(middleware.CORS(middleware.CORSConfig{
+//		AllowedOrigins:   []string{"https://example.com", "https://*.example.com"},
+//		AllowCredentials: true,
+//		ExposedHeaders:   []string{"X-Request-ID"},
+//		MaxAge:           time.Hour,
+//	}))
 func CORS(config ...CORSConfig) gsk.Middleware {
 	var corsConfig CORSConfig
 	if len(config) > 0 {
 		corsConfig = config[0]
-	} else {
-		corsConfig = CORSConfig{
-			AllowedOrigins: []string{defaultCORSOrigin},
-			AllowedMethods: defaultAllowMethods,
-			AllowedHeaders: defaultAllowHeaders,
-			AllowAll:       false,
-		}
-	}
-
-	allowedMethods := strings.Join(defaultAllowMethods, ", ")
-	if len(corsConfig.AllowedMethods) != 0 {
-		allowedMethods = strings.Join(corsConfig.AllowedMethods, ", ")
-	}
-	allowedHeaders := strings.Join(defaultAllowHeaders, ", ")
-	if len(corsConfig.AllowedHeaders) != 0 {
-		allowedHeaders = strings.Join(corsConfig.AllowedHeaders, ", ")
 	}
+	policy := newCORSPolicy(corsConfig)
 
 	return func(next gsk.HandlerFunc) gsk.HandlerFunc {
 		return func(c *gsk.Context) {
-			allowedOrigins := getAllowedOrigins(corsConfig.AllowedOrigins)
-
+			headers := c.Writer.Header()
 			origin := c.Origin()
-			// Check if the origin is in the allowedOrigins list
-			isAllowed := false
-			for _, allowedOrigin := range allowedOrigins {
-				if allowedOrigin == "same-origin" || allowedOrigin == "*" || origin == allowedOrigin {
-					isAllowed = true
-					break
-				}
+			preflight := c.Request.Method == http.MethodOptions && c.Request.Header.Get(AccessControlRequestMethod) != ""
+
+			// responses differ by the origin, unless any origin gets the same "*" response
+			if !policy.allowAll {
+				addVary(headers, "Origin")
+			}
+			if preflight {
+				addVary(headers, AccessControlRequestMethod)
+				addVary(headers, AccessControlRequestHeaders)
 			}
 
-			if !corsConfig.AllowAll && !isAllowed {
-				c.Status(http.StatusForbidden)
-				c.SetHeader("Content-Type", "text/plain")
-				c.RawResponse([]byte("Forbidden"))
+			if origin == "" || isSameOrigin(origin, c.Scheme(), c.Request.Host) {
+				next(c)
 				return
 			}
 
-			// Set CORS headers
-			headers := c.Writer.Header()
+			if !policy.isOriginAllowed(origin) {
+				forbidden(c)
+				return
+			}
+
+			if preflight {
+				requestedHeaders := c.Request.Header.Get(AccessControlRequestHeaders)
+				if !policy.isMethodAllowed(c.Request.Header.Get(AccessControlRequestMethod)) || !policy.areHeadersAllowed(requestedHeaders) {
+					forbidden(c)
+					return
+				}
 
-			headers.Set(AccessControlAllowOrigin, origin)
-			headers.Set(AccessControlAllowMethods, allowedMethods)
-			headers.Set(AccessControlAllowHeaders, allowedHeaders)
-			headers.Set(AccessControlAllowCredentials, defaultAllowCredentials)
+				policy.setOrigin(headers, origin)
+				headers.Set(AccessControlAllowMethods, policy.allowedMethods)
+				if policy.anyHeader {
+					if requestedHeaders != "" {
+						headers.Set(AccessControlAllowHeaders, requestedHeaders)
+					}
+				} else {
+					headers.Set(AccessControlAllowHeaders, policy.allowedHeaders)
+				}
+				if policy.maxAge != "" {
+					headers.Set(AccessControlMaxAge, policy.maxAge)
+				}
+
+				c.Status(http.StatusNoContent)
+				return
+			}
+
+			policy.setOrigin(headers, origin)
+			if policy.exposedHeaders != "" {
+				headers.Set(AccessControlExposeHeaders, policy.exposedHeaders)
+			}
 
 			next(c)
+		}
+	}
+}
+
+func newCORSPolicy(config CORSConfig) *corsPolicy {
+	policy := &corsPolicy{
+		allowAll:         config.AllowAll,
+		origins:          map[string]bool{},
+		allowOriginFunc:  config.AllowOriginFunc,
+		methods:          config.AllowedMethods,
+		headers:          config.AllowedHeaders,
+		allowCredentials: config.AllowCredentials,
+		exposedHeaders:   strings.Join(config.ExposedHeaders, ", "),
+	}
 
+	for _, origin := range config.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		if origin == "*" {
+			policy.allowAll = true
+		} else if origin == nullOrigin {
+			panic("middleware: CORS can not allow the null origin")
+		} else if prefix, suffix, ok := strings.Cut(origin, "*"); ok {
+			policy.patterns = append(policy.patterns, originPattern{prefix: prefix, suffix: suffix})
+		} else {
+			policy.origins[origin] = true
 		}
 	}
+
+	// any site could make requests with the cookies of the users
+	if policy.allowAll && policy.allowCredentials {
+		panic("middleware: CORS AllowAll can not be used with AllowCredentials, list the AllowedOrigins or use AllowOriginFunc")
+	}
+
+	if len(policy.methods) == 0 {
+		policy.methods = defaultAllowMethods
+	}
+	if len(policy.headers) == 0 {
+		policy.headers = defaultAllowHeaders
+	}
+	for _, header := range policy.headers {
+		if header == "*" {
+			policy.anyHeader = true
+		}
+	}
+	policy.allowedMethods = strings.Join(policy.methods, ", ")
+	policy.allowedHeaders = strings.Join(policy.headers, ", ")
+
+	if config.MaxAge > 0 {
+		policy.maxAge = strconv.Itoa(int(config.MaxAge.Seconds()))
+	} else if config.MaxAge < 0 {
+		// disables the caching of the preflight responses
+		policy.maxAge = "0"
+	}
+
+	return policy
 }
 
-func getAllowedOrigins(origins []string) []string {
-	var allowedOrigins []string
+// nullOrigin is sent by sandboxed iframes, local files and redirects, any site can send it
+const nullOrigin = "null"
 
-	if len(origins) == 0 {
-		allowedOrigins = []string{defaultCORSOrigin}
-	} else {
-		allowedOrigins = origins
+func (p *corsPolicy) isOriginAllowed(origin string) bool {
+	if strings.EqualFold(origin, nullOrigin) {
+		return false
 	}
-	return allowedOrigins
+	if p.allowAll {
+		return true
+	}
+
+	normalized := strings.ToLower(origin)
+	if p.origins[normalized] {
+		return true
+	}
+	for _, pattern := range p.patterns {
+		if pattern.match(normalized) {
+			return true
+		}
+	}
+
+	return p.allowOriginFunc != nil && p.allowOriginFunc(origin)
+}
+
+// setOrigin allows the origin, "*" is sent when any origin is allowed
+func (p *corsPolicy) setOrigin(headers http.Header, origin string) {
+	if p.allowAll {
+		headers.Set(AccessControlAllowOrigin, "*")
+		return
+	}
+
+	headers.Set(AccessControlAllowOrigin, origin)
+	if p.allowCredentials {
+		headers.Set(AccessControlAllowCredentials, "true")
+	}
+}
+
+// isMethodAllowed checks the requested method, methods are case sensitive
+func (p *corsPolicy) isMethodAllowed(method string) bool {
+	for _, allowed := range p.methods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+// areHeadersAllowed checks the comma separated requested headers, headers are case insensitive
+func (p *corsPolicy) areHeadersAllowed(requested string) bool {
+	if p.anyHeader {
+		return true
+	}
+
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+
+		allowed := false
+		for _, allowedHeader := range p.headers {
+			if strings.EqualFold(allowedHeader, header) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// match checks the origin against the pattern, the wildcard matches one or more subdomains
+func (p originPattern) match(origin string) bool {
+	if len(origin) <= len(p.prefix)+len(p.suffix) || !strings.HasPrefix(origin, p.prefix) || !strings.HasSuffix(origin, p.suffix) {
+		return false
+	}
+
+	subdomain := origin[len(p.prefix) : len(origin)-len(p.suffix)]
+	if strings.HasPrefix(subdomain, ".") || strings.HasSuffix(subdomain, ".") {
+		return false
+	}
+	for _, char := range subdomain {
+		isLabelChar := char >= 'a' && char <= 'z' || char >= '0' && char <= '9' || char == '-' || char == '.'
+		if !isLabelChar {
+			return false
+		}
+	}
+	return true
+}
+
+// isSameOrigin checks if the origin is the scheme and host the request is sent to
+// the default port of the scheme is ignored, eg: https://example.com is the same as example.com:443
+func isSameOrigin(origin string, scheme string, host string) bool {
+	originURL, err := url.Parse(origin)
+	if err != nil || originURL.Host == "" || !strings.EqualFold(originURL.Scheme, scheme) {
+		return false
+	}
+	return strings.EqualFold(withoutDefaultPort(originURL.Host, scheme), withoutDefaultPort(host, scheme))
+}
+
+// withoutDefaultPort removes the default port of the scheme from the host
+func withoutDefaultPort(host string, scheme string) string {
+	switch {
+	case strings.EqualFold(scheme, "http"):
+		return strings.TrimSuffix(host, ":80")
+	case strings.EqualFold(scheme, "https"):
+		return strings.TrimSuffix(host, ":443")
+	}
+	return host
+}
+
+func forbidden(c *gsk.Context) {
+	c.Status(http.StatusForbidden)
+	c.SetHeader("Content-Type", "text/plain")
+	c.RawResponse([]byte("Forbidden"))
 }
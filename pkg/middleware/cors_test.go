@@ -2,7 +2,10 @@ package middleware_test
 
 import (
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/adharshmk96/stk/gsk"
 	"github.com/adharshmk96/stk/pkg/middleware"
@@ -23,31 +26,66 @@ func TestCORSDefault(t *testing.T) {
 		c.Status(http.StatusOK).JSONResponse("OK")
 	})
 
-	t.Run("Non-preflight request", func(t *testing.T) {
-		// Run the test request
+	t.Run("Request without origin", func(t *testing.T) {
+		rr, _ := s.Test("GET", "/", nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "Origin", rr.Header().Get("Vary"))
+	})
+
+	t.Run("Same origin request", func(t *testing.T) {
 		testParams := gsk.TestParams{
 			Headers: map[string]string{
-				"Origin": "example.com",
+				"Origin": "http://localhost:8888",
 			},
 		}
-		rr, _ := s.Test("GET", "/", nil, testParams)
+		rr, _ := s.Test("GET", "http://localhost:8888/", nil, testParams)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+	})
 
-		expectedHeaders := map[string]string{
-			"Access-Control-Allow-Origin":  "example.com",
-			"Access-Control-Allow-Methods": "POST, GET, OPTIONS, PUT, DELETE, PATCH",
-			"Access-Control-Allow-Headers": "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization",
+	t.Run("Same host with another scheme", func(t *testing.T) {
+		testParams := gsk.TestParams{
+			Headers: map[string]string{
+				"Origin": "https://localhost:8888",
+			},
 		}
+		rr, _ := s.Test("GET", "http://localhost:8888/", nil, testParams)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
 
-		// expect http.StatusOK
-		if rr.Code != http.StatusOK {
-			t.Errorf("Expected response code %d, but got %d", http.StatusOK, rr.Code)
-		}
+	t.Run("Same origin request through a trusted proxy", func(t *testing.T) {
+		s := gsk.New(&gsk.ServerConfig{TrustedProxies: []string{"10.0.0.0/8"}})
+		s.Use(middleware.CORS())
+		s.Get("/", func(c *gsk.Context) {
+			c.StringResponse("OK")
+		})
+
+		req := httptest.NewRequest("GET", "http://example.com/", nil)
+		req.RemoteAddr = "10.0.0.2:80"
+		req.Header.Set("Origin", "https://example.com")
+		req.Header.Set("X-Forwarded-Proto", "https")
+		rr := httptest.NewRecorder()
+		s.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
 
-		for header, expectedValue := range expectedHeaders {
-			if value := rr.Header().Get(header); value != expectedValue {
-				t.Errorf("Expected %s header to be %q, but got %q", header, expectedValue, value)
-			}
+		req.RemoteAddr = "203.0.113.5:5123"
+		rr = httptest.NewRecorder()
+		s.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Cross origin request", func(t *testing.T) {
+		testParams := gsk.TestParams{
+			Headers: map[string]string{
+				"Origin": "https://example.com",
+			},
 		}
+		rr, _ := s.Test("GET", "/", nil, testParams)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
 	})
 
 }
@@ -59,7 +97,7 @@ func TestCORSAllowedOrigin(t *testing.T) {
 	}
 
 	AllowedOrigins := []string{
-		"example.com",
+		"https://example.com",
 	}
 	s := gsk.New(config)
 
@@ -77,22 +115,24 @@ func TestCORSAllowedOrigin(t *testing.T) {
 		// Run the test request
 		testParams := gsk.TestParams{
 			Headers: map[string]string{
-				"Origin": "example.com",
+				"Origin": "https://example.com",
 			},
 		}
 		rr, _ := s.Test("GET", "/", nil, testParams)
 
 		expectedHeaders := map[string]string{
-			"Access-Control-Allow-Origin":  "example.com",
-			"Access-Control-Allow-Methods": "POST, GET, OPTIONS, PUT, DELETE, PATCH",
-			"Access-Control-Allow-Headers": "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization",
+			"Access-Control-Allow-Origin":      "https://example.com",
+			"Access-Control-Allow-Methods":     "",
+			"Access-Control-Allow-Headers":     "",
+			"Access-Control-Allow-Credentials": "",
+			"Vary":                             "Origin",
 		}
 
 		assert.Equal(t, http.StatusOK, rr.Code)
 
 		for header, expectedValue := range expectedHeaders {
 			value := rr.Header().Get(header)
-			assert.Equal(t, expectedValue, value)
+			assert.Equal(t, expectedValue, value, header)
 		}
 	})
 
@@ -101,7 +141,7 @@ func TestCORSAllowedOrigin(t *testing.T) {
 		// Run the test request
 		testParams := gsk.TestParams{
 			Headers: map[string]string{
-				"Origin": "invalid.com",
+				"Origin": "https://invalid.com",
 			},
 		}
 		rr, _ := s.Test("GET", "/", nil, testParams)
@@ -125,16 +165,14 @@ func TestCORSAllowedOrigin(t *testing.T) {
 		// Run the test request
 		testParams := gsk.TestParams{
 			Headers: map[string]string{
-				"Origin":                        "example.com",
+				"Origin":                        "https://example.com",
 				"Access-Control-Request-Method": "POST",
 			},
 		}
 		rr, _ := s.Test("OPTIONS", "/", nil, testParams)
 
-		// NOTE: thie is behaviour from the router package
-		// change this if we are chaning the router
 		expectedHeaders := map[string]string{
-			"Access-Control-Allow-Origin":  "example.com",
+			"Access-Control-Allow-Origin":  "https://example.com",
 			"Access-Control-Allow-Methods": "POST, GET, OPTIONS, PUT, DELETE, PATCH",
 			"Access-Control-Allow-Headers": "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization",
 		}
@@ -152,7 +190,7 @@ func TestCORSAllowedOrigin(t *testing.T) {
 		// Run the test request
 		testParams := gsk.TestParams{
 			Headers: map[string]string{
-				"Origin":                        "invalid.com",
+				"Origin":                        "https://invalid.com",
 				"Access-Control-Request-Method": "POST",
 			},
 		}
@@ -164,7 +202,6 @@ func TestCORSAllowedOrigin(t *testing.T) {
 			"Access-Control-Allow-Headers": "",
 		}
 
-		// TODO - this should be checked later on
 		assert.Equal(t, http.StatusForbidden, rr.Code)
 
 		for header, expectedValue := range expectedHeaders {
@@ -174,3 +211,320 @@ func TestCORSAllowedOrigin(t *testing.T) {
 	})
 
 }
+
+func TestCORSConfig(t *testing.T) {
+	t.Run("panics for any origin with credentials", func(t *testing.T) {
+		assert.Panics(t, func() {
+			middleware.CORS(middleware.CORSConfig{AllowAll: true, AllowCredentials: true})
+		})
+		assert.Panics(t, func() {
+			middleware.CORS(middleware.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+		})
+	})
+
+	t.Run("panics for the null origin", func(t *testing.T) {
+		assert.Panics(t, func() {
+			middleware.CORS(middleware.CORSConfig{AllowedOrigins: []string{"https://example.com", "null"}})
+		})
+	})
+}
+
+func TestCORSConformance(t *testing.T) {
+	// absent is expected for the headers which must not be set
+	const absent = "<absent>"
+
+	tests := []struct {
+		name     string
+		config   middleware.CORSConfig
+		method   string
+		headers  map[string]string
+		status   int
+		expected map[string]string
+	}{
+		{
+			name:    "allow all sends the wildcard origin",
+			config:  middleware.CORSConfig{AllowAll: true},
+			method:  "GET",
+			headers: map[string]string{"Origin": "https://any.com"},
+			status:  http.StatusOK,
+			expected: map[string]string{
+				"Access-Control-Allow-Origin":      "*",
+				"Access-Control-Allow-Credentials": absent,
+				"Vary":                             absent,
+			},
+		},
+		{
+			name:    "wildcard in allowed origins is the same as allow all",
+			config:  middleware.CORSConfig{AllowedOrigins: []string{"*"}},
+			method:  "GET",
+			headers: map[string]string{"Origin": "https://any.com"},
+			status:  http.StatusOK,
+			expected: map[string]string{
+				"Access-Control-Allow-Origin": "*",
+			},
+		},
+		{
+			name:    "allow all refuses the null origin",
+			config:  middleware.CORSConfig{AllowAll: true},
+			method:  "GET",
+			headers: map[string]string{"Origin": "null"},
+			status:  http.StatusForbidden,
+			expected: map[string]string{
+				"Access-Control-Allow-Origin": absent,
+			},
+		},
+		{
+			name: "allow origin func refuses the null origin",
+			config: middleware.CORSConfig{
+				AllowOriginFunc: func(origin string) bool { return true },
+			},
+			method:  "GET",
+			headers: map[string]string{"Origin": "null"},
+			status:  http.StatusForbidden,
+		},
+		{
+			name: "allow origin func with credentials echoes the origin",
+			config: middleware.CORSConfig{
+				AllowOriginFunc:  func(origin string) bool { return origin == "https://tenant.com" },
+				AllowCredentials: true,
+			},
+			method:  "GET",
+			headers: map[string]string{"Origin": "https://tenant.com"},
+			status:  http.StatusOK,
+			expected: map[string]string{
+				"Access-Control-Allow-Origin":      "https://tenant.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			name:    "credentials are not allowed by default",
+			config:  middleware.CORSConfig{AllowedOrigins: []string{"https://example.com"}},
+			method:  "GET",
+			headers: map[string]string{"Origin": "https://example.com"},
+			status:  http.StatusOK,
+			expected: map[string]string{
+				"Access-Control-Allow-Credentials": absent,
+			},
+		},
+		{
+			name:    "origins are matched case insensitively",
+			config:  middleware.CORSConfig{AllowedOrigins: []string{"https://Example.com"}},
+			method:  "GET",
+			headers: map[string]string{"Origin": "https://example.COM"},
+			status:  http.StatusOK,
+			expected: map[string]string{
+				"Access-Control-Allow-Origin": "https://example.COM",
+			},
+		},
+		{
+			name:    "origins with other schemes are rejected",
+			config:  middleware.CORSConfig{AllowedOrigins: []string{"https://example.com"}},
+			method:  "GET",
+			headers: map[string]string{"Origin": "http://example.com"},
+			status:  http.StatusForbidden,
+		},
+		{
+			name:    "wildcard pattern matches subdomains",
+			config:  middleware.CORSConfig{AllowedOrigins: []string{"https://*.example.com"}},
+			method:  "GET",
+			headers: map[string]string{"Origin": "https://api.eu.example.com"},
+			status:  http.StatusOK,
+			expected: map[string]string{
+				"Access-Control-Allow-Origin": "https://api.eu.example.com",
+			},
+		},
+		{
+			name:    "wildcard pattern does not match the parent domain",
+			config:  middleware.CORSConfig{AllowedOrigins: []string{"https://*.example.com"}},
+			method:  "GET",
+			headers: map[string]string{"Origin": "https://example.com"},
+			status:  http.StatusForbidden,
+		},
+		{
+			name:    "wildcard pattern does not match other domains",
+			config:  middleware.CORSConfig{AllowedOrigins: []string{"https://*.example.com"}},
+			method:  "GET",
+			headers: map[string]string{"Origin": "https://evil.com/.example.com"},
+			status:  http.StatusForbidden,
+		},
+		{
+			name:    "wildcard pattern does not match suffixes of other domains",
+			config:  middleware.CORSConfig{AllowedOrigins: []string{"https://*.example.com"}},
+			method:  "GET",
+			headers: map[string]string{"Origin": "https://evilexample.com"},
+			status:  http.StatusForbidden,
+		},
+		{
+			name: "origin validator allows origins",
+			config: middleware.CORSConfig{AllowOriginFunc: func(origin string) bool {
+				return strings.HasSuffix(origin, ".tenant.io")
+			}},
+			method:  "GET",
+			headers: map[string]string{"Origin": "https://acme.tenant.io"},
+			status:  http.StatusOK,
+			expected: map[string]string{
+				"Access-Control-Allow-Origin": "https://acme.tenant.io",
+			},
+		},
+		{
+			name: "origin validator rejects origins",
+			config: middleware.CORSConfig{AllowOriginFunc: func(origin string) bool {
+				return strings.HasSuffix(origin, ".tenant.io")
+			}},
+			method:  "GET",
+			headers: map[string]string{"Origin": "https://other.io"},
+			status:  http.StatusForbidden,
+		},
+		{
+			name: "exposed headers are set on actual requests",
+			config: middleware.CORSConfig{
+				AllowedOrigins: []string{"https://example.com"},
+				ExposedHeaders: []string{"X-Request-ID", "X-Total-Count"},
+			},
+			method:  "GET",
+			headers: map[string]string{"Origin": "https://example.com"},
+			status:  http.StatusOK,
+			expected: map[string]string{
+				"Access-Control-Expose-Headers": "X-Request-ID, X-Total-Count",
+				"Access-Control-Allow-Methods":  absent,
+				"Access-Control-Max-Age":        absent,
+			},
+		},
+		{
+			name:    "options request without request method is not a preflight",
+			config:  middleware.CORSConfig{AllowedOrigins: []string{"https://example.com"}},
+			method:  "OPTIONS",
+			headers: map[string]string{"Origin": "https://example.com"},
+			status:  http.StatusNoContent,
+			expected: map[string]string{
+				"Access-Control-Allow-Origin":  "https://example.com",
+				"Access-Control-Allow-Methods": absent,
+				"Allow":                        "GET, OPTIONS",
+			},
+		},
+		{
+			name: "preflight sets the max age and credentials",
+			config: middleware.CORSConfig{
+				AllowedOrigins:   []string{"https://example.com"},
+				AllowCredentials: true,
+				MaxAge:           10 * time.Minute,
+				ExposedHeaders:   []string{"X-Request-ID"},
+			},
+			method: "OPTIONS",
+			headers: map[string]string{
+				"Origin":                         "https://example.com",
+				"Access-Control-Request-Method":  "PUT",
+				"Access-Control-Request-Headers": "content-type, authorization",
+			},
+			status: http.StatusNoContent,
+			expected: map[string]string{
+				"Access-Control-Allow-Origin":      "https://example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Max-Age":           "600",
+				"Access-Control-Expose-Headers":    absent,
+				"Vary":                             "Origin, Access-Control-Request-Method, Access-Control-Request-Headers",
+			},
+		},
+		{
+			name: "negative max age disables the preflight cache",
+			config: middleware.CORSConfig{
+				AllowedOrigins: []string{"https://example.com"},
+				MaxAge:         -1,
+			},
+			method: "OPTIONS",
+			headers: map[string]string{
+				"Origin":                        "https://example.com",
+				"Access-Control-Request-Method": "GET",
+			},
+			status: http.StatusNoContent,
+			expected: map[string]string{
+				"Access-Control-Max-Age": "0",
+			},
+		},
+		{
+			name: "preflight uses the configured methods and headers",
+			config: middleware.CORSConfig{
+				AllowedOrigins: []string{"https://example.com"},
+				AllowedMethods: []string{"GET", "POST"},
+				AllowedHeaders: []string{"X-Custom"},
+			},
+			method: "OPTIONS",
+			headers: map[string]string{
+				"Origin":                         "https://example.com",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "x-custom",
+			},
+			status: http.StatusNoContent,
+			expected: map[string]string{
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Allow-Headers": "X-Custom",
+			},
+		},
+		{
+			name: "preflight rejects methods not allowed",
+			config: middleware.CORSConfig{
+				AllowedOrigins: []string{"https://example.com"},
+				AllowedMethods: []string{"GET", "POST"},
+			},
+			method: "OPTIONS",
+			headers: map[string]string{
+				"Origin":                        "https://example.com",
+				"Access-Control-Request-Method": "DELETE",
+			},
+			status: http.StatusForbidden,
+			expected: map[string]string{
+				"Access-Control-Allow-Origin": absent,
+			},
+		},
+		{
+			name:   "preflight rejects headers not allowed",
+			config: middleware.CORSConfig{AllowedOrigins: []string{"https://example.com"}},
+			method: "OPTIONS",
+			headers: map[string]string{
+				"Origin":                         "https://example.com",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "content-type, x-secret",
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "preflight reflects the requested headers when any header is allowed",
+			config: middleware.CORSConfig{
+				AllowedOrigins: []string{"https://example.com"},
+				AllowedHeaders: []string{"*"},
+			},
+			method: "OPTIONS",
+			headers: map[string]string{
+				"Origin":                         "https://example.com",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "x-anything, content-type",
+			},
+			status: http.StatusNoContent,
+			expected: map[string]string{
+				"Access-Control-Allow-Headers": "x-anything, content-type",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := gsk.New()
+			s.Use(middleware.CORS(tt.config))
+			s.Get("/", func(c *gsk.Context) {
+				c.StringResponse("OK")
+			})
+
+			rr, _ := s.Test(tt.method, "/", nil, gsk.TestParams{Headers: tt.headers})
+
+			assert.Equal(t, tt.status, rr.Code)
+			for header, expectedValue := range tt.expected {
+				if expectedValue == absent {
+					assert.Empty(t, rr.Header().Values(header), header)
+					continue
+				}
+				assert.Equal(t, expectedValue, strings.Join(rr.Header().Values(header), ", "), header)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/adharshmk96/stk/pkg/metrics"
+)
+
+// DefaultSizeBuckets are the histogram buckets for response sizes in bytes
+var DefaultSizeBuckets = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+
+type MetricsConfig struct {
+	// Registry to record the metrics in, default metrics.DefaultRegistry
+	Registry *metrics.Registry
+	// Namespace is prefixed to the metric names, eg: myapp_http_requests_total
+	Namespace string
+	// DurationBuckets are the buckets of the latency histogram in seconds, default metrics.DefaultBuckets
+	DurationBuckets []float64
+	// SizeBuckets are the buckets of the response size histogram in bytes, default DefaultSizeBuckets
+	SizeBuckets []float64
+}
+
+// Metrics records the requests in the registry
+// - http_requests_total : counter of requests by method, route and status
+// - http_request_duration_seconds : histogram of latencies by method, route and status
+// - http_response_size_bytes : histogram of response sizes by method, route and status
+// - http_requests_in_flight : gauge of requests being handled by method and route
+// requests are labeled by the route pattern, eg: /users/:id, to keep the number of series bounded
+// usage example:
+// server.Use(middleware.Metrics())
+// server.MetricsHandler("/metrics")
+func Metrics(config ...MetricsConfig) gsk.Middleware {
+	var metricsConfig MetricsConfig
+	if len(config) > 0 {
+		metricsConfig = config[0]
+	}
+	if metricsConfig.Registry == nil {
+		metricsConfig.Registry = metrics.DefaultRegistry
+	}
+	if len(metricsConfig.SizeBuckets) == 0 {
+		metricsConfig.SizeBuckets = DefaultSizeBuckets
+	}
+
+	prefix := ""
+	if metricsConfig.Namespace != "" {
+		prefix = metricsConfig.Namespace + "_"
+	}
+
+	registry := metricsConfig.Registry
+	requests := registry.Counter(prefix+"http_requests_total", "Total number of HTTP requests.", "method", "route", "status")
+	duration := registry.Histogram(prefix+"http_request_duration_seconds", "HTTP request latency in seconds.", metricsConfig.DurationBuckets, "method", "route", "status")
+	size := registry.Histogram(prefix+"http_response_size_bytes", "HTTP response size in bytes.", metricsConfig.SizeBuckets, "method", "route", "status")
+	inFlight := registry.Gauge(prefix+"http_requests_in_flight", "Number of HTTP requests being handled.", "method", "route")
+
+	return func(next gsk.HandlerFunc) gsk.HandlerFunc {
+		return func(c *gsk.Context) {
+			method := c.Request.Method
+			route := c.Route()
+			if route == "" {
+				route = "unmatched"
+			}
+
+			startTime := time.Now()
+			gauge := inFlight.With(method, route)
+			gauge.Inc()
+			defer gauge.Dec()
+
+			completed := false
+			defer func() {
+				status := c.GetStatusCode()
+				if !completed {
+					// a panic in the next handlers, it is written as 500 by the recoverer outside
+					status = http.StatusInternalServerError
+				} else if status == 0 {
+					status = http.StatusOK
+				}
+				statusLabel := strconv.Itoa(status)
+				requests.With(method, route, statusLabel).Inc()
+				duration.With(method, route, statusLabel).Observe(time.Since(startTime).Seconds())
+				size.With(method, route, statusLabel).Observe(float64(len(c.GetResponseBody())))
+			}()
+
+			next(c)
+			completed = true
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package middleware_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/adharshmk96/stk/pkg/metrics"
+	"github.com/adharshmk96/stk/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+
+	s := gsk.New(&gsk.ServerConfig{Port: "8888"})
+	s.Use(middleware.Metrics(middleware.MetricsConfig{
+		Registry:  registry,
+		Namespace: "app",
+	}))
+
+	s.Get("/users/:id", func(c *gsk.Context) {
+		c.StringResponse("user")
+	})
+	s.Get("/fail", func(c *gsk.Context) {
+		panic("boom")
+	})
+	s.MetricsHandler("/metrics", registry)
+
+	s.Test("GET", "/users/1", nil)
+	s.Test("GET", "/users/2", nil)
+	s.Test("GET", "/fail", nil)
+
+	rr, _ := s.Test("GET", "/metrics", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, metrics.ContentType, rr.Header().Get("Content-Type"))
+
+	body := rr.Body.String()
+	expectedLines := []string{
+		`app_http_requests_total{method="GET",route="/users/:id",status="200"} 2`,
+		`app_http_requests_total{method="GET",route="/fail",status="500"} 1`,
+		`app_http_request_duration_seconds_count{method="GET",route="/users/:id",status="200"} 2`,
+		`app_http_response_size_bytes_bucket{method="GET",route="/users/:id",status="200",le="100"} 2`,
+		`app_http_response_size_bytes_sum{method="GET",route="/users/:id",status="200"} 8`,
+		`app_http_requests_in_flight{method="GET",route="/users/:id"} 0`,
+		// the in-flight metrics request itself
+		`app_http_requests_in_flight{method="GET",route="/metrics"} 1`,
+	}
+	for _, line := range expectedLines {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.False(t, strings.Contains(body, "/users/1"), "raw paths are not used as labels")
+}
+
+func TestMetrics_Panic(t *testing.T) {
+	t.Run("records panics in the later middlewares", func(t *testing.T) {
+		registry := metrics.NewRegistry()
+
+		s := gsk.New()
+		s.Use(middleware.Metrics(middleware.MetricsConfig{Registry: registry}))
+		s.Use(func(next gsk.HandlerFunc) gsk.HandlerFunc {
+			return func(c *gsk.Context) {
+				if c.Route() == "/broken" {
+					panic("boom")
+				}
+				next(c)
+			}
+		})
+		s.Get("/broken", func(c *gsk.Context) {})
+
+		rr, _ := s.Test("GET", "/broken", nil)
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+		var exposition strings.Builder
+		registry.WriteTo(&exposition)
+		assert.Contains(t, exposition.String(), `http_requests_total{method="GET",route="/broken",status="500"} 1`+"\n")
+		assert.Contains(t, exposition.String(), `http_request_duration_seconds_count{method="GET",route="/broken",status="500"} 1`+"\n")
+		assert.Contains(t, exposition.String(), `http_requests_in_flight{method="GET",route="/broken"} 0`+"\n")
+	})
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+const (
+	memoryStoreShards = 32
+	// expired keys of a shard are removed every sweepInterval updates
+	sweepInterval = 1024
+)
+
+// RateLimitStore keeps the state of the rate limited clients, it must be safe for concurrent use
+type RateLimitStore interface {
+	// Update passes the state of the key to fn and stores the returned state for the ttl
+	// the update is atomic, concurrent updates of the key see each other's state
+	// the state is nil when the key is missing or expired
+	Update(ctx context.Context, key string, ttl time.Duration, fn func(state []byte) ([]byte, error)) error
+}
+
+// MemoryStore is an in-memory RateLimitStore, the keys are split into shards to reduce the lock contention
+type MemoryStore struct {
+	shards []*memoryShard
+}
+
+type memoryShard struct {
+	mu      sync.Mutex
+	items   map[string]memoryItem
+	updates int
+}
+
+type memoryItem struct {
+	state   []byte
+	expires time.Time
+}
+
+// NewMemoryStore creates an in-memory store, the state is not shared between instances of the server
+func NewMemoryStore() *MemoryStore {
+	shards := make([]*memoryShard, memoryStoreShards)
+	for i := range shards {
+		shards[i] = &memoryShard{items: map[string]memoryItem{}}
+	}
+	return &MemoryStore{shards: shards}
+}
+
+func (s *MemoryStore) Update(ctx context.Context, key string, ttl time.Duration, fn func(state []byte) ([]byte, error)) error {
+	shard := s.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	now := time.Now()
+	shard.sweep(now)
+
+	var state []byte
+	if item, ok := shard.items[key]; ok && now.Before(item.expires) {
+		state = item.state
+	}
+
+	state, err := fn(state)
+	if err != nil {
+		return err
+	}
+
+	shard.items[key] = memoryItem{state: state, expires: now.Add(ttl)}
+	return nil
+}
+
+// Len returns the number of keys in the store, including the expired ones not removed yet
+func (s *MemoryStore) Len() int {
+	count := 0
+	for _, shard := range s.shards {
+		shard.mu.Lock()
+		count += len(shard.items)
+		shard.mu.Unlock()
+	}
+	return count
+}
+
+func (s *MemoryStore) shard(key string) *memoryShard {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return s.shards[hash.Sum32()%uint32(len(s.shards))]
+}
+
+// sweep removes the expired keys periodically, instead of a goroutine per key
+func (shard *memoryShard) sweep(now time.Time) {
+	shard.updates++
+	if shard.updates < sweepInterval {
+		return
+	}
+	shard.updates = 0
+	for key, item := range shard.items {
+		if !now.Before(item.expires) {
+			delete(shard.items, key)
+		}
+	}
+}
@@ -1,24 +1,67 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/adharshmk96/stk/gsk"
 )
 
-type RateLimiter struct {
-	requestsPerInterval int
-	interval            time.Duration
-	accessCounter       map[string]int
-	mux                 *sync.Mutex
-	Middleware          gsk.Middleware
-}
+// ErrTooManyRequests is the response for the requests over the rate limit
+var ErrTooManyRequests = gsk.NewHTTPError(http.StatusTooManyRequests, "too_many_requests", "too many requests, please try again later")
+
+// RateLimitAlgorithm decides how the requests are counted
+type RateLimitAlgorithm int
+
+const (
+	// TokenBucket refills RequestsPerInterval tokens every interval into a bucket of Burst tokens,
+	// a request takes a token, so bursts up to the bucket size are allowed
+	TokenBucket RateLimitAlgorithm = iota
+	// SlidingWindow allows RequestsPerInterval requests in any interval,
+	// estimated from the counts of the current and the previous fixed window
+	SlidingWindow
+)
+
+// KeyFunc returns the key the requests are counted by, requests with an empty key are not limited
+type KeyFunc func(c *gsk.Context) string
 
 type RateLimiterConfig struct {
+	// RequestsPerInterval is the number of requests allowed in the interval, default 5
 	RequestsPerInterval int
-	Interval            time.Duration
+	// Interval is the duration of the limit, default 1 second
+	Interval time.Duration
+	// Algorithm counts the requests, default TokenBucket
+	Algorithm RateLimitAlgorithm
+	// Burst is the size of the bucket of TokenBucket, default RequestsPerInterval
+	Burst int
+	// KeyFunc returns the key of the client, default KeyByIP
+	KeyFunc KeyFunc
+	// Store keeps the state of the clients, default an in-memory store
+	// use a shared store, eg: NewRedisStore, to limit the requests across instances
+	Store RateLimitStore
+	// Prefix is added to the keys in the store, set different prefixes for limiters sharing a store, default ratelimit
+	Prefix string
+}
+
+// RateLimitResult is the state of the limit for a request
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the limit is fully available again
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, set when the request is not allowed
+	RetryAfter time.Duration
+}
+
+type RateLimiter struct {
+	config     RateLimiterConfig
+	Middleware gsk.Middleware
 }
 
 func initConfig(config ...RateLimiterConfig) *RateLimiterConfig {
@@ -35,42 +78,68 @@ func initConfig(config ...RateLimiterConfig) *RateLimiterConfig {
 	if initConfig.Interval == 0 {
 		initConfig.Interval = 1 * time.Second
 	}
+	if initConfig.Burst == 0 {
+		initConfig.Burst = initConfig.RequestsPerInterval
+	}
+	if initConfig.KeyFunc == nil {
+		initConfig.KeyFunc = KeyByIP
+	}
+	if initConfig.Store == nil {
+		initConfig.Store = NewMemoryStore()
+	}
+	if initConfig.Prefix == "" {
+		initConfig.Prefix = "ratelimit"
+	}
 
 	return initConfig
 }
 
+// NewRateLimiter creates a rate limiter, the requests over the limit get a 429 Too Many Requests response
+// RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers are set on the responses,
+// and Retry-After on the limited responses
+// requests are allowed when the store fails, the error is logged
+// usage example:
+//
+//	server.Use(middleware.NewRateLimiter().Middleware)
+//
+//	loginLimiter := middleware.NewRateLimiter(middleware.RateLimiterConfig{
+//		RequestsPerInterval: 10,
+//		Interval:            time.Minute,
+//		Algorithm:           middleware.SlidingWindow,
+//		Prefix:              "login",
+//	})
+//	server.Post("/login", handler.Login, loginLimiter.Middleware)
 func NewRateLimiter(rlConfig ...RateLimiterConfig) *RateLimiter {
 	config := initConfig(rlConfig...)
 
 	rl := &RateLimiter{
-		requestsPerInterval: config.RequestsPerInterval,
-		interval:            config.Interval,
-		accessCounter:       make(map[string]int),
-		mux:                 &sync.Mutex{},
+		config: *config,
 	}
 
 	middleware := func(next gsk.HandlerFunc) gsk.HandlerFunc {
 		return func(c *gsk.Context) {
-			clientIP := c.Request.RemoteAddr
-			rl.mux.Lock()
-			defer rl.mux.Unlock()
-
-			if cnt, ok := rl.accessCounter[clientIP]; ok {
-				if cnt >= rl.requestsPerInterval {
-					c.Status(http.StatusTooManyRequests).JSONResponse(gsk.Map{
-						"error": "Too many requests. Please try again later.",
-					})
-					return
-				}
-				rl.accessCounter[clientIP]++
-			} else {
-				rl.accessCounter[clientIP] = 1
-				go func(ip string) {
-					time.Sleep(rl.interval)
-					rl.mux.Lock()
-					defer rl.mux.Unlock()
-					delete(rl.accessCounter, ip)
-				}(clientIP)
+			key := rl.config.KeyFunc(c)
+			if key == "" {
+				next(c)
+				return
+			}
+
+			result, err := rl.Allow(c.Request.Context(), key)
+			if err != nil {
+				c.Logger().Error("error in rate limiter store", "error", err)
+				next(c)
+				return
+			}
+
+			headers := c.Writer.Header()
+			headers.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			headers.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			headers.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+			if !result.Allowed {
+				headers.Set("Retry-After", strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1)))
+				c.ErrorResponse(ErrTooManyRequests)
+				return
 			}
 
 			next(c)
@@ -82,3 +151,166 @@ func NewRateLimiter(rlConfig ...RateLimiterConfig) *RateLimiter {
 	return rl
 
 }
+
+// Allow counts a request for the key and returns whether it is allowed
+func (rl *RateLimiter) Allow(ctx context.Context, key string) (RateLimitResult, error) {
+	var result RateLimitResult
+	limit, interval := rl.config.RequestsPerInterval, rl.config.Interval
+
+	ttl := 2 * interval
+	if rl.config.Algorithm == TokenBucket {
+		// a bucket is full again after the ttl, so a missing state is the same
+		ttl = time.Duration(math.Ceil(float64(rl.config.Burst) * float64(interval) / float64(limit)))
+	}
+
+	err := rl.config.Store.Update(ctx, rl.config.Prefix+":"+key, ttl, func(state []byte) ([]byte, error) {
+		var err error
+		now := time.Now()
+		if rl.config.Algorithm == SlidingWindow {
+			state, result, err = slidingWindow(state, now, limit, interval)
+		} else {
+			state, result, err = tokenBucket(state, now, limit, interval, rl.config.Burst)
+		}
+		return state, err
+	})
+	return result, err
+}
+
+// tokenBucket takes a token from the bucket, the state is tokens:unix nano time of the last refill
+func tokenBucket(state []byte, now time.Time, limit int, interval time.Duration, burst int) ([]byte, RateLimitResult, error) {
+	capacity := float64(burst)
+	rate := float64(limit) / float64(interval) // tokens per nanosecond
+
+	tokens, last := capacity, now.UnixNano()
+	if len(state) > 0 {
+		var err error
+		if tokens, last, err = parseTokenBucket(state); err != nil {
+			return nil, RateLimitResult{}, err
+		}
+		elapsed := float64(max(now.UnixNano()-last, 0))
+		tokens = math.Min(capacity, tokens+elapsed*rate)
+	}
+
+	result := RateLimitResult{Limit: burst}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - tokens) / rate)
+	}
+	result.Remaining = int(tokens)
+	result.Reset = time.Duration((capacity - tokens) / rate)
+
+	state = []byte(strconv.FormatFloat(tokens, 'f', -1, 64) + ":" + strconv.FormatInt(now.UnixNano(), 10))
+	return state, result, nil
+}
+
+func parseTokenBucket(state []byte) (float64, int64, error) {
+	tokens, last, _ := strings.Cut(string(state), ":")
+	t, err := strconv.ParseFloat(tokens, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid token bucket state %q", state)
+	}
+	l, err := strconv.ParseInt(last, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid token bucket state %q", state)
+	}
+	return t, l, nil
+}
+
+// slidingWindow counts the request in the current window, the requests of the previous window are weighted
+// by the part of it still in the sliding interval, the state is window start:count:previous count
+func slidingWindow(state []byte, now time.Time, limit int, interval time.Duration) ([]byte, RateLimitResult, error) {
+	windowStart := now.UnixNano() / int64(interval) * int64(interval)
+
+	start, count, previous := windowStart, 0, 0
+	if len(state) > 0 {
+		var err error
+		if start, count, previous, err = parseSlidingWindow(state); err != nil {
+			return nil, RateLimitResult{}, err
+		}
+		if start < windowStart {
+			if start == windowStart-int64(interval) {
+				previous = count
+			} else {
+				previous = 0
+			}
+			start, count = windowStart, 0
+		}
+	}
+
+	elapsed := time.Duration(now.UnixNano() - windowStart)
+	weight := 1 - float64(elapsed)/float64(interval)
+	estimated := float64(previous)*weight + float64(count)
+
+	result := RateLimitResult{Limit: limit, Reset: interval - elapsed}
+	if estimated+1 <= float64(limit) {
+		count++
+		estimated++
+		result.Allowed = true
+	} else if count+1 > limit {
+		// the current window is full, wait for its requests to slide out in the next window
+		result.RetryAfter = result.Reset + time.Duration(float64(interval)*(1-float64(limit-1)/float64(count)))
+	} else {
+		// wait for enough requests of the previous window to slide out
+		result.RetryAfter = time.Duration(float64(interval)*(1-float64(limit-count-1)/float64(previous))) - elapsed
+	}
+	result.Remaining = max(limit-int(math.Ceil(estimated)), 0)
+
+	state = []byte(fmt.Sprintf("%d:%d:%d", start, count, previous))
+	return state, result, nil
+}
+
+func parseSlidingWindow(state []byte) (int64, int, int, error) {
+	var start int64
+	var count, previous int
+	if _, err := fmt.Sscanf(string(state), "%d:%d:%d", &start, &count, &previous); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid sliding window state %q", state)
+	}
+	return start, count, previous, nil
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// KeyByIP counts the requests by the client IP, see gsk.Context.ClientIP for the proxy configuration
+// requests without a remote address are counted together
+func KeyByIP(c *gsk.Context) string {
+	if ip := c.ClientIP(); ip != "" {
+		return ip
+	}
+	return "unknown"
+}
+
+// KeyByHeader counts the requests by the value of the request header, eg: X-API-Key
+func KeyByHeader(name string) KeyFunc {
+	return func(c *gsk.Context) string {
+		return c.Request.Header.Get(name)
+	}
+}
+
+// KeyByContext counts the requests by a value set in the context, eg: the user id set by the auth middleware
+// usage example:
+// middleware.KeyByContext(userIDKey{})
+func KeyByContext(key any) KeyFunc {
+	return func(c *gsk.Context) string {
+		value := c.Get(key)
+		if value == nil {
+			return ""
+		}
+		return fmt.Sprint(value)
+	}
+}
+
+// KeyByRoute counts the requests of each route separately, by the key of the key function
+// eg: middleware.KeyByRoute(middleware.KeyByIP)
+func KeyByRoute(keyFunc KeyFunc) KeyFunc {
+	return func(c *gsk.Context) string {
+		key := keyFunc(c)
+		if key == "" {
+			return ""
+		}
+		return c.Request.Method + " " + c.Route() + ":" + key
+	}
+}
@@ -1,7 +1,11 @@
 package middleware_test
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -48,3 +52,212 @@ func TestRateLimiter(t *testing.T) {
 
 	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
 }
+
+func TestRateLimiterResponses(t *testing.T) {
+	t.Run("sets the rate limit headers", func(t *testing.T) {
+		s := gsk.New()
+		limiter := middleware.NewRateLimiter(middleware.RateLimiterConfig{
+			RequestsPerInterval: 2,
+			Interval:            time.Minute,
+		})
+		s.Get("/", dummyHandler, limiter.Middleware)
+
+		rr, _ := s.Test("GET", "/", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "2", rr.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", rr.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", rr.Header().Get("RateLimit-Reset"))
+
+		s.Test("GET", "/", nil)
+		rr, _ = s.Test("GET", "/", nil)
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+		assert.Contains(t, rr.Body.String(), "too_many_requests")
+	})
+
+	t.Run("counts the requests by the client IP", func(t *testing.T) {
+		s := gsk.New()
+		limiter := middleware.NewRateLimiter(middleware.RateLimiterConfig{
+			RequestsPerInterval: 1,
+			Interval:            time.Minute,
+		})
+		s.Get("/", func(c *gsk.Context) {
+			c.StringResponse("OK")
+		}, func(next gsk.HandlerFunc) gsk.HandlerFunc {
+			return func(c *gsk.Context) {
+				c.Request.RemoteAddr = c.Request.Header.Get("X-Test-Addr")
+				next(c)
+			}
+		}, limiter.Middleware)
+
+		request := func(addr string) int {
+			rr, _ := s.Test("GET", "/", nil, gsk.TestParams{Headers: map[string]string{"X-Test-Addr": addr}})
+			return rr.Code
+		}
+
+		assert.Equal(t, http.StatusOK, request("10.0.0.1:1000"))
+		// a new connection of the same client is limited
+		assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.1:2000"))
+		assert.Equal(t, http.StatusOK, request("10.0.0.2:1000"))
+	})
+
+	t.Run("counts the requests by the key function", func(t *testing.T) {
+		s := gsk.New()
+		limiter := middleware.NewRateLimiter(middleware.RateLimiterConfig{
+			RequestsPerInterval: 1,
+			Interval:            time.Minute,
+			KeyFunc:             middleware.KeyByRoute(middleware.KeyByHeader("X-API-Key")),
+		})
+		s.Use(limiter.Middleware)
+		s.Get("/users", dummyHandler)
+		s.Get("/orders", dummyHandler)
+
+		request := func(path, key string) int {
+			rr, _ := s.Test("GET", path, nil, gsk.TestParams{Headers: map[string]string{"X-API-Key": key}})
+			return rr.Code
+		}
+
+		assert.Equal(t, http.StatusOK, request("/users", "a"))
+		assert.Equal(t, http.StatusTooManyRequests, request("/users", "a"))
+		assert.Equal(t, http.StatusOK, request("/users", "b"))
+		assert.Equal(t, http.StatusOK, request("/orders", "a"))
+
+		// requests without a key are not limited
+		assert.Equal(t, http.StatusOK, request("/users", ""))
+		assert.Equal(t, http.StatusOK, request("/users", ""))
+	})
+
+	t.Run("limits the routes separately", func(t *testing.T) {
+		s := gsk.New()
+		s.Use(middleware.NewRateLimiter(middleware.RateLimiterConfig{
+			RequestsPerInterval: 3,
+			Interval:            time.Minute,
+		}).Middleware)
+
+		loginLimiter := middleware.NewRateLimiter(middleware.RateLimiterConfig{
+			RequestsPerInterval: 1,
+			Interval:            time.Minute,
+			Prefix:              "login",
+		})
+		s.Post("/login", dummyHandler, loginLimiter.Middleware)
+		s.Get("/", dummyHandler)
+
+		rr, _ := s.Test("POST", "/login", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		rr, _ = s.Test("POST", "/login", nil)
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+
+		rr, _ = s.Test("GET", "/", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		rr, _ = s.Test("GET", "/", nil)
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	})
+}
+
+func TestRateLimiterAlgorithms(t *testing.T) {
+	t.Run("token bucket allows bursts and refills", func(t *testing.T) {
+		limiter := middleware.NewRateLimiter(middleware.RateLimiterConfig{
+			RequestsPerInterval: 1,
+			Interval:            50 * time.Millisecond,
+			Burst:               3,
+		})
+		ctx := context.Background()
+
+		for i := 0; i < 3; i++ {
+			result, err := limiter.Allow(ctx, "client")
+			assert.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, 3, result.Limit)
+			assert.Equal(t, 2-i, result.Remaining)
+		}
+
+		result, _ := limiter.Allow(ctx, "client")
+		assert.False(t, result.Allowed)
+		assert.Greater(t, result.RetryAfter, time.Duration(0))
+		assert.LessOrEqual(t, result.RetryAfter, 50*time.Millisecond)
+
+		time.Sleep(60 * time.Millisecond)
+		result, _ = limiter.Allow(ctx, "client")
+		assert.True(t, result.Allowed)
+		result, _ = limiter.Allow(ctx, "client")
+		assert.False(t, result.Allowed)
+	})
+
+	t.Run("sliding window allows the limit in any interval", func(t *testing.T) {
+		limiter := middleware.NewRateLimiter(middleware.RateLimiterConfig{
+			RequestsPerInterval: 3,
+			Interval:            100 * time.Millisecond,
+			Algorithm:           middleware.SlidingWindow,
+		})
+		ctx := context.Background()
+
+		for i := 0; i < 3; i++ {
+			result, err := limiter.Allow(ctx, "client")
+			assert.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, 2-i, result.Remaining)
+		}
+
+		result, _ := limiter.Allow(ctx, "client")
+		assert.False(t, result.Allowed)
+		assert.Greater(t, result.RetryAfter, time.Duration(0))
+		assert.LessOrEqual(t, result.RetryAfter, 200*time.Millisecond)
+
+		// the requests slide out of the interval
+		time.Sleep(result.RetryAfter + 10*time.Millisecond)
+		result, _ = limiter.Allow(ctx, "client")
+		assert.True(t, result.Allowed)
+
+		time.Sleep(250 * time.Millisecond)
+		for i := 0; i < 3; i++ {
+			result, _ = limiter.Allow(ctx, "client")
+			assert.True(t, result.Allowed)
+		}
+	})
+
+	t.Run("counts concurrent requests", func(t *testing.T) {
+		store := middleware.NewMemoryStore()
+		limiter := middleware.NewRateLimiter(middleware.RateLimiterConfig{
+			RequestsPerInterval: 50,
+			Interval:            time.Minute,
+			Store:               store,
+		})
+
+		var allowed atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if result, _ := limiter.Allow(context.Background(), "client"); result.Allowed {
+					allowed.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(50), allowed.Load())
+		assert.Equal(t, 1, store.Len())
+	})
+
+	t.Run("allows the requests when the store fails", func(t *testing.T) {
+		s := gsk.New()
+		limiter := middleware.NewRateLimiter(middleware.RateLimiterConfig{
+			RequestsPerInterval: 1,
+			Store:               failingStore{},
+		})
+		s.Get("/", dummyHandler, limiter.Middleware)
+
+		for i := 0; i < 3; i++ {
+			rr, _ := s.Test("GET", "/", nil)
+			assert.Equal(t, http.StatusOK, rr.Code)
+		}
+	})
+}
+
+type failingStore struct{}
+
+func (failingStore) Update(ctx context.Context, key string, ttl time.Duration, fn func(state []byte) ([]byte, error)) error {
+	return errors.New("store is down")
+}
@@ -0,0 +1,14 @@
+package middleware
+
+import "github.com/adharshmk96/stk/gsk"
+
+// Recover recovers from panics in the next handlers, logs the stack trace and
+// writes a 500 response through the server's ErrorHandler
+// The server recovers from panics by default, use this middleware along with
+// gsk.ServerConfig{DisableRecover: true} to place the recovery in the middleware chain
+// usage example:
+// server.Use(middleware.RequestLogger)
+// server.Use(middleware.Recover(gsk.RecoverConfig{Reporter: reportToSentry}))
+func Recover(config ...gsk.RecoverConfig) gsk.Middleware {
+	return gsk.Recover(config...)
+}
@@ -0,0 +1,39 @@
+package middleware_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/adharshmk96/stk/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover(t *testing.T) {
+	var status int
+
+	config := &gsk.ServerConfig{
+		Port:           "8888",
+		DisableRecover: true,
+	}
+	s := gsk.New(config)
+
+	// status is available to the outer middlewares after recovery
+	s.Use(func(next gsk.HandlerFunc) gsk.HandlerFunc {
+		return func(c *gsk.Context) {
+			next(c)
+			status = c.GetStatusCode()
+		}
+	})
+	s.Use(middleware.Recover())
+
+	s.Get("/", func(c *gsk.Context) {
+		panic("boom")
+	})
+
+	rr, _ := s.Test("GET", "/", nil)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.JSONEq(t, `{"error": "internal_server_error"}`, rr.Body.String())
+}
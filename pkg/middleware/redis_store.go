@@ -0,0 +1,321 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRedisPoolSize   = 10
+	defaultRedisTimeout    = time.Second
+	defaultRedisMaxRetries = 10
+)
+
+// ErrRedisConflict is returned when the key is changed by other clients in all the retries of an update
+var ErrRedisConflict = errors.New("redis: too many conflicting updates")
+
+type RedisStoreConfig struct {
+	// Addr is the address of the redis server, default localhost:6379
+	Addr     string
+	Password string
+	DB       int
+	// PoolSize is the maximum number of idle connections, default 10
+	PoolSize int
+	// Timeout limits connecting and each update, default 1 second
+	Timeout time.Duration
+	// MaxRetries is the number of attempts of an update when the key is changed concurrently, default 10
+	MaxRetries int
+}
+
+// RedisStore is a RateLimitStore in redis, or any server speaking the redis protocol,
+// so the limits are shared by the instances of the server
+// updates are optimistic transactions with WATCH, MULTI and EXEC
+type RedisStore struct {
+	config RedisStoreConfig
+	pool   chan *redisConn
+}
+
+// NewRedisStore creates a store connecting to the redis server on demand
+// usage example:
+//
+//	store := middleware.NewRedisStore(middleware.RedisStoreConfig{Addr: "redis:6379"})
+//	limiter := middleware.NewRateLimiter(middleware.RateLimiterConfig{Store: store})
+func NewRedisStore(config RedisStoreConfig) *RedisStore {
+	if config.Addr == "" {
+		config.Addr = "localhost:6379"
+	}
+	if config.PoolSize <= 0 {
+		config.PoolSize = defaultRedisPoolSize
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultRedisTimeout
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = defaultRedisMaxRetries
+	}
+
+	return &RedisStore{
+		config: config,
+		pool:   make(chan *redisConn, config.PoolSize),
+	}
+}
+
+func (s *RedisStore) Update(ctx context.Context, key string, ttl time.Duration, fn func(state []byte) ([]byte, error)) error {
+	deadline := time.Now().Add(s.config.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	conn, err := s.conn(ctx, deadline)
+	if err != nil {
+		return err
+	}
+
+	err = s.update(conn, key, ttl, fn)
+	s.release(conn, err)
+	return err
+}
+
+func (s *RedisStore) update(conn *redisConn, key string, ttl time.Duration, fn func(state []byte) ([]byte, error)) error {
+	ttlMillis := strconv.FormatInt(max(ttl.Milliseconds(), 1), 10)
+
+	for i := 0; i < s.config.MaxRetries; i++ {
+		if _, err := conn.do("WATCH", key); err != nil {
+			return err
+		}
+
+		reply, err := conn.do("GET", key)
+		if err != nil {
+			return err
+		}
+		state, _ := reply.([]byte)
+
+		state, err = fn(state)
+		if err != nil {
+			if _, unwatchErr := conn.do("UNWATCH"); unwatchErr != nil {
+				return unwatchErr
+			}
+			return &redisFnError{err}
+		}
+
+		replies, err := conn.pipeline(
+			[]string{"MULTI"},
+			[]string{"SET", key, string(state), "PX", ttlMillis},
+			[]string{"EXEC"},
+		)
+		if err != nil {
+			return err
+		}
+
+		// EXEC replies nil when the watched key was changed by another client
+		if replies[2] != nil {
+			return nil
+		}
+	}
+
+	return ErrRedisConflict
+}
+
+// Close closes the idle connections
+func (s *RedisStore) Close() error {
+	for {
+		select {
+		case conn := <-s.pool:
+			conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// conn returns an idle connection or connects to the server
+func (s *RedisStore) conn(ctx context.Context, deadline time.Time) (*redisConn, error) {
+	select {
+	case conn := <-s.pool:
+		conn.SetDeadline(deadline)
+		return conn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Deadline: deadline}
+	netConn, err := dialer.DialContext(ctx, "tcp", s.config.Addr)
+	if err != nil {
+		return nil, err
+	}
+	netConn.SetDeadline(deadline)
+
+	conn := &redisConn{Conn: netConn, reader: bufio.NewReader(netConn)}
+	if s.config.Password != "" {
+		if _, err := conn.do("AUTH", s.config.Password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if s.config.DB != 0 {
+		if _, err := conn.do("SELECT", strconv.Itoa(s.config.DB)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// release returns the connection to the pool when no WATCH or MULTI is left on it
+// connections with error replies, network or protocol errors are closed, since an error reply
+// can leave the key watched and abort the transaction of the next update on the connection
+func (s *RedisStore) release(conn *redisConn, err error) {
+	var fnErr *redisFnError
+	if err != nil && !errors.As(err, &fnErr) && !errors.Is(err, ErrRedisConflict) {
+		conn.Close()
+		return
+	}
+
+	select {
+	case s.pool <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// redisFnError is an error returned by the update function, the connection is still usable
+type redisFnError struct {
+	err error
+}
+
+func (e *redisFnError) Error() string {
+	return e.err.Error()
+}
+
+func (e *redisFnError) Unwrap() error {
+	return e.err
+}
+
+// redisError is an error reply of the server
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// redisConn is a connection speaking RESP, the redis serialization protocol
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (conn *redisConn) do(args ...string) (interface{}, error) {
+	replies, err := conn.pipeline(args)
+	if err != nil {
+		return nil, err
+	}
+	return replies[0], nil
+}
+
+// pipeline sends the commands together and reads their replies
+// error replies of the queued commands are returned after all the replies are read
+func (conn *redisConn) pipeline(commands ...[]string) ([]interface{}, error) {
+	var buf []byte
+	for _, args := range commands {
+		buf = appendCommand(buf, args)
+	}
+	if _, err := conn.Write(buf); err != nil {
+		return nil, err
+	}
+
+	var replyErr error
+	replies := make([]interface{}, len(commands))
+	for i := range commands {
+		reply, err := readReply(conn.reader)
+		var redisErr redisError
+		if errors.As(err, &redisErr) {
+			if replyErr == nil {
+				replyErr = err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		replies[i] = reply
+	}
+	return replies, replyErr
+}
+
+// appendCommand encodes the command as an array of bulk strings
+func appendCommand(buf []byte, args []string) []byte {
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	return buf
+}
+
+// readReply reads a reply, simple strings as string, bulk strings as []byte,
+// integers as int64, arrays as []interface{}, nil bulk strings and arrays as nil
+func readReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := readLine(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	case '*':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, size)
+		for i := range items {
+			item, err := readReply(reader)
+			var redisErr redisError
+			if err != nil && !errors.As(err, &redisErr) {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: invalid reply %q", line)
+}
+
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("redis: invalid reply %q", line)
+	}
+	return line[:len(line)-2], nil
+}
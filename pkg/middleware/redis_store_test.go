@@ -0,0 +1,322 @@
+package middleware_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/adharshmk96/stk/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedis is a stand-in redis server supporting the commands used by the RedisStore
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu          sync.Mutex
+	values      map[string]fakeValue
+	versions    map[string]int
+	commands    []string
+	connections int
+}
+
+type fakeValue struct {
+	data    string
+	expires time.Time
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := &fakeRedis{
+		listener: listener,
+		password: password,
+		values:   map[string]fakeValue{},
+		versions: map[string]int{},
+	}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (r *fakeRedis) addr() string {
+	return r.listener.Addr().String()
+}
+
+func (r *fakeRedis) get(key string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	value, ok := r.values[key]
+	if !ok || time.Now().After(value.expires) {
+		return "", false
+	}
+	return value.data, true
+}
+
+func (r *fakeRedis) ttl(key string) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return time.Until(r.values[key].expires)
+}
+
+func (r *fakeRedis) serve() {
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			return
+		}
+		r.mu.Lock()
+		r.connections++
+		r.mu.Unlock()
+		go r.handle(conn)
+	}
+}
+
+func (r *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	authenticated := r.password == ""
+	watched := map[string]int{}
+	var queued [][]string
+	inMulti := false
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		r.mu.Lock()
+		r.commands = append(r.commands, args[0])
+		r.mu.Unlock()
+
+		command := strings.ToUpper(args[0])
+		switch {
+		case command == "AUTH":
+			if args[1] != r.password {
+				io.WriteString(conn, "-WRONGPASS invalid password\r\n")
+				continue
+			}
+			authenticated = true
+			io.WriteString(conn, "+OK\r\n")
+		case !authenticated:
+			io.WriteString(conn, "-NOAUTH authentication required\r\n")
+		case inMulti && command != "EXEC":
+			queued = append(queued, args)
+			io.WriteString(conn, "+QUEUED\r\n")
+		case command == "SELECT":
+			io.WriteString(conn, "+OK\r\n")
+		case command == "WATCH":
+			r.mu.Lock()
+			for _, key := range args[1:] {
+				watched[key] = r.versions[key]
+			}
+			r.mu.Unlock()
+			io.WriteString(conn, "+OK\r\n")
+		case command == "UNWATCH":
+			watched = map[string]int{}
+			io.WriteString(conn, "+OK\r\n")
+		case command == "GET" && strings.HasPrefix(args[1], "list:"):
+			io.WriteString(conn, "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n")
+		case command == "GET":
+			if value, ok := r.get(args[1]); ok {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+			} else {
+				io.WriteString(conn, "$-1\r\n")
+			}
+		case command == "MULTI":
+			inMulti = true
+			io.WriteString(conn, "+OK\r\n")
+		case command == "EXEC":
+			r.mu.Lock()
+			changed := false
+			for key, version := range watched {
+				if r.versions[key] != version {
+					changed = true
+				}
+			}
+			if changed {
+				io.WriteString(conn, "*-1\r\n")
+			} else {
+				fmt.Fprintf(conn, "*%d\r\n", len(queued))
+				for _, args := range queued {
+					r.set(args)
+					io.WriteString(conn, "+OK\r\n")
+				}
+			}
+			r.mu.Unlock()
+			inMulti, queued, watched = false, nil, map[string]int{}
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+		}
+	}
+}
+
+// set runs SET key value PX milliseconds, the lock is held by the caller
+func (r *fakeRedis) set(args []string) {
+	millis, _ := strconv.Atoi(args[4])
+	r.values[args[1]] = fakeValue{data: args[2], expires: time.Now().Add(time.Duration(millis) * time.Millisecond)}
+	r.versions[args[1]]++
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+func increment(state []byte) ([]byte, error) {
+	count, _ := strconv.Atoi(string(state))
+	return []byte(strconv.Itoa(count + 1)), nil
+}
+
+func TestRedisStore(t *testing.T) {
+	t.Run("updates the state with a ttl", func(t *testing.T) {
+		redis := newFakeRedis(t, "")
+		store := middleware.NewRedisStore(middleware.RedisStoreConfig{Addr: redis.addr()})
+		defer store.Close()
+
+		var seen [][]byte
+		for i := 0; i < 3; i++ {
+			err := store.Update(context.Background(), "counter", time.Minute, func(state []byte) ([]byte, error) {
+				seen = append(seen, state)
+				return increment(state)
+			})
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, [][]byte{nil, []byte("1"), []byte("2")}, seen)
+		value, _ := redis.get("counter")
+		assert.Equal(t, "3", value)
+		assert.InDelta(t, time.Minute.Seconds(), redis.ttl("counter").Seconds(), 1)
+	})
+
+	t.Run("concurrent updates are atomic", func(t *testing.T) {
+		redis := newFakeRedis(t, "")
+		store := middleware.NewRedisStore(middleware.RedisStoreConfig{Addr: redis.addr(), MaxRetries: 1000})
+		defer store.Close()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, store.Update(context.Background(), "counter", time.Minute, increment))
+			}()
+		}
+		wg.Wait()
+
+		value, _ := redis.get("counter")
+		assert.Equal(t, "20", value)
+	})
+
+	t.Run("authenticates with the password", func(t *testing.T) {
+		redis := newFakeRedis(t, "secret")
+
+		store := middleware.NewRedisStore(middleware.RedisStoreConfig{Addr: redis.addr(), Password: "secret", DB: 2})
+		assert.NoError(t, store.Update(context.Background(), "counter", time.Minute, increment))
+		store.Close()
+
+		store = middleware.NewRedisStore(middleware.RedisStoreConfig{Addr: redis.addr(), Password: "wrong"})
+		err := store.Update(context.Background(), "counter", time.Minute, increment)
+		assert.ErrorContains(t, err, "WRONGPASS")
+	})
+
+	t.Run("returns the errors of the update function", func(t *testing.T) {
+		redis := newFakeRedis(t, "")
+		store := middleware.NewRedisStore(middleware.RedisStoreConfig{Addr: redis.addr()})
+		defer store.Close()
+
+		errInvalid := errors.New("invalid state")
+		err := store.Update(context.Background(), "counter", time.Minute, func(state []byte) ([]byte, error) {
+			return nil, errInvalid
+		})
+		assert.ErrorIs(t, err, errInvalid)
+
+		// the connection is reused after the error
+		assert.NoError(t, store.Update(context.Background(), "counter", time.Minute, increment))
+		assert.Contains(t, redis.commands, "UNWATCH")
+	})
+
+	t.Run("closes the connection after an error reply", func(t *testing.T) {
+		redis := newFakeRedis(t, "")
+		store := middleware.NewRedisStore(middleware.RedisStoreConfig{Addr: redis.addr()})
+		defer store.Close()
+
+		err := store.Update(context.Background(), "list:counter", time.Minute, increment)
+		assert.ErrorContains(t, err, "WRONGTYPE")
+
+		// the watched key is not left on a pooled connection
+		assert.NoError(t, store.Update(context.Background(), "counter", time.Minute, increment))
+		redis.mu.Lock()
+		defer redis.mu.Unlock()
+		assert.Equal(t, 2, redis.connections)
+	})
+
+	t.Run("fails when the server is not reachable", func(t *testing.T) {
+		listener, _ := net.Listen("tcp", "127.0.0.1:0")
+		addr := listener.Addr().String()
+		listener.Close()
+
+		store := middleware.NewRedisStore(middleware.RedisStoreConfig{Addr: addr, Timeout: 100 * time.Millisecond})
+		assert.Error(t, store.Update(context.Background(), "counter", time.Minute, increment))
+	})
+
+	t.Run("limits the requests across servers", func(t *testing.T) {
+		redis := newFakeRedis(t, "")
+		store := middleware.NewRedisStore(middleware.RedisStoreConfig{Addr: redis.addr()})
+		defer store.Close()
+
+		newServer := func() *gsk.Server {
+			s := gsk.New()
+			limiter := middleware.NewRateLimiter(middleware.RateLimiterConfig{
+				RequestsPerInterval: 2,
+				Interval:            time.Minute,
+				Store:               store,
+			})
+			s.Get("/", dummyHandler, limiter.Middleware)
+			return s
+		}
+		first, second := newServer(), newServer()
+
+		rr, _ := first.Test("GET", "/", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		rr, _ = second.Test("GET", "/", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		rr, _ = first.Test("GET", "/", nil)
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	})
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/adharshmk96/stk/gsk"
+)
+
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+type RequestIDConfig struct {
+	// Header is the request and response header of the request id, default X-Request-ID
+	Header string
+	// Generator generates the id when the request has no valid id, default random uuid v4
+	Generator func() string
+}
+
+// RequestID sets a correlation id for every request
+// The incoming request id header is used if valid, otherwise a new id is generated.
+// The id is set in the response header and added to the logger as request_id,
+// add it before the RequestLogger so that the request logs carry the id
+// usage example:
+// server.Use(middleware.RequestID())
+// server.Use(middleware.RequestLogger)
+func RequestID(config ...RequestIDConfig) gsk.Middleware {
+	var requestIDConfig RequestIDConfig
+	if len(config) > 0 {
+		requestIDConfig = config[0]
+	}
+	if requestIDConfig.Header == "" {
+		requestIDConfig.Header = gsk.HeaderRequestID
+	}
+	if requestIDConfig.Generator == nil {
+		requestIDConfig.Generator = newRequestID
+	}
+
+	return func(next gsk.HandlerFunc) gsk.HandlerFunc {
+		return func(c *gsk.Context) {
+			id := c.Request.Header.Get(requestIDConfig.Header)
+			if !isValidRequestID(id) {
+				id = requestIDConfig.Generator()
+				c.Request.Header.Set(requestIDConfig.Header, id)
+			}
+
+			c.Writer.Header().Set(requestIDConfig.Header, id)
+			c.Set(requestIDKey{}, id)
+			c.SetLogger(c.Logger().With("request_id", id))
+
+			next(c)
+		}
+	}
+}
+
+// GetRequestID returns the request id set by the RequestID middleware
+func GetRequestID(c *gsk.Context) string {
+	id, _ := c.Get(requestIDKey{}).(string)
+	return id
+}
+
+// isValidRequestID accepts printable ascii ids without spaces
+// to avoid log injection through the header
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random uuid v4
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,69 @@
+package middleware_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/adharshmk96/stk/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	newServer := func(config ...middleware.RequestIDConfig) (*gsk.Server, *string, *bytes.Buffer) {
+		logs := &bytes.Buffer{}
+		s := gsk.New(&gsk.ServerConfig{
+			Port:   "8888",
+			Logger: slog.New(slog.NewJSONHandler(logs, nil)),
+		})
+		s.Use(middleware.RequestID(config...))
+
+		var handlerID string
+		s.Get("/", func(c *gsk.Context) {
+			handlerID = middleware.GetRequestID(c)
+			c.Logger().Info("handled")
+		})
+		return s, &handlerID, logs
+	}
+
+	t.Run("uses the incoming request id", func(t *testing.T) {
+		s, handlerID, logs := newServer()
+
+		rr, _ := s.Test("GET", "/", nil, gsk.TestParams{
+			Headers: map[string]string{"X-Request-ID": "abc-123"},
+		})
+
+		assert.Equal(t, "abc-123", rr.Header().Get("X-Request-ID"))
+		assert.Equal(t, "abc-123", *handlerID)
+		assert.Contains(t, logs.String(), `"request_id":"abc-123"`)
+	})
+
+	t.Run("generates a request id when missing or invalid", func(t *testing.T) {
+		s, handlerID, _ := newServer()
+		uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+		rr, _ := s.Test("GET", "/", nil)
+		assert.Regexp(t, uuidPattern, rr.Header().Get("X-Request-ID"))
+		assert.Equal(t, rr.Header().Get("X-Request-ID"), *handlerID)
+
+		rr, _ = s.Test("GET", "/", nil, gsk.TestParams{
+			Headers: map[string]string{"X-Request-ID": "bad id\nforged=log"},
+		})
+		assert.Regexp(t, uuidPattern, rr.Header().Get("X-Request-ID"))
+	})
+
+	t.Run("uses custom header and generator", func(t *testing.T) {
+		s, _, _ := newServer(middleware.RequestIDConfig{
+			Header:    "X-Correlation-ID",
+			Generator: func() string { return "generated" },
+		})
+
+		rr, _ := s.Test("GET", "/", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "generated", rr.Header().Get("X-Correlation-ID"))
+		assert.Empty(t, rr.Header().Get("X-Request-ID"))
+	})
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/adharshmk96/stk/pkg/tracing"
+)
+
+// TraceContext continues the W3C trace from the traceparent and tracestate headers
+// or starts a new trace when the headers are missing or invalid.
+// The request is handled in a new span of the trace, the trace context is stored in the
+// request context and trace_id, span_id are added to the logger.
+// When the server has a Tracer, the ids of the request span are used
+// usage example:
+// server.Use(middleware.TraceContext)
+//
+// propagate the trace to outgoing requests:
+// tc, _ := tracing.FromContext(c.Request.Context())
+// tc.Inject(outgoingRequest.Header)
+func TraceContext(next gsk.HandlerFunc) gsk.HandlerFunc {
+	return func(c *gsk.Context) {
+		// the request span started by the server's Tracer already continues the trace
+		tc, ok := tracing.FromContext(c.Request.Context())
+		if !ok {
+			var err error
+			tc, err = tracing.Extract(c.Request.Header)
+			if err != nil {
+				tc = tracing.New()
+			} else {
+				tc = tc.Child()
+			}
+			c.Request = c.Request.WithContext(tracing.ContextWithTrace(c.Request.Context(), tc))
+		}
+
+		c.SetLogger(c.Logger().With("trace_id", tc.TraceID, "span_id", tc.SpanID))
+
+		next(c)
+	}
+}
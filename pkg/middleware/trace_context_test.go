@@ -0,0 +1,52 @@
+package middleware_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/adharshmk96/stk/gsk"
+	"github.com/adharshmk96/stk/pkg/middleware"
+	"github.com/adharshmk96/stk/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceContext(t *testing.T) {
+	logs := &bytes.Buffer{}
+	s := gsk.New(&gsk.ServerConfig{
+		Port:   "8888",
+		Logger: slog.New(slog.NewJSONHandler(logs, nil)),
+	})
+	s.Use(middleware.TraceContext)
+
+	var tc tracing.TraceContext
+	s.Get("/", func(c *gsk.Context) {
+		tc, _ = tracing.FromContext(c.Request.Context())
+		c.Logger().Info("handled")
+	})
+
+	t.Run("continues the incoming trace in a new span", func(t *testing.T) {
+		logs.Reset()
+		s.Test("GET", "/", nil, gsk.TestParams{
+			Headers: map[string]string{
+				"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				"tracestate":  "congo=t61rcWkgMzE",
+			},
+		})
+
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID)
+		assert.NotEqual(t, "00f067aa0ba902b7", tc.SpanID)
+		assert.Equal(t, "congo=t61rcWkgMzE", tc.State)
+		assert.Contains(t, logs.String(), `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+		assert.Contains(t, logs.String(), `"span_id":"`+tc.SpanID+`"`)
+	})
+
+	t.Run("starts a new trace for invalid traceparent", func(t *testing.T) {
+		s.Test("GET", "/", nil, gsk.TestParams{
+			Headers: map[string]string{"traceparent": "invalid"},
+		})
+
+		assert.True(t, tc.IsValid())
+		assert.NotEqual(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID)
+	})
+}
@@ -118,8 +118,17 @@ type {{ .ExportedName }}Handlers struct {
 }
 
 // {{ .ExportedName }}Handler provides a mock function with given fields: gc
-func (_m *{{ .ExportedName }}Handlers) {{ .ExportedName }}Handler(gc *gsk.Context) {
-	_m.Called(gc)
+func (_m *{{ .ExportedName }}Handlers) {{ .ExportedName }}Handler(gc *gsk.Context) error {
+	ret := _m.Called(gc)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gsk.Context) error); ok {
+		r0 = rf(gc)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 type mockConstructorTestingTNew{{ .ExportedName }}Handlers interface {
@@ -183,7 +192,10 @@ func SetupApiRoutes(rg *gsk.RouteGroup) {
 
 	{{ .ModName }}Routes := rg.RouteGroup("/{{ .ModName }}")
 
-	{{ .ModName }}Routes.Get("/", {{ .ModName }}Handler.{{ .ExportedName }}Handler)
+	{{ .ModName }}Routes.Get("/", gsk.E({{ .ModName }}Handler.{{ .ExportedName }}Handler)).HandlerName("handler.{{ .ExportedName }}Handler").Document(gsk.RouteDoc{
+		Summary: "{{ .ExportedName }} the database",
+		Tags:    []string{"{{ .ModName }}"},
+	})
 }
 
 func SetupWebRoutes(rg *gsk.RouteGroup) {
@@ -217,7 +229,7 @@ import (
 func HomeHandler(gc *gsk.Context) {
 
 	gc.TemplateResponse(&gsk.Tpl{
-		TemplatePath: "public/templates/index.html",
+		TemplatePath: "index.html",
 		Variables: gsk.Map{
 			"Title":   "{{ .ExportedName }}",
 			"Content": "Welcome to the {{ .ModName }} page!",
@@ -307,6 +319,7 @@ import (
 	"testing"
 
 	"{{ .PkgName }}/internals/{{ .ModName }}/api/handler"
+	"{{ .PkgName }}/internals/{{ .ModName }}/serr"
 
 	"github.com/adharshmk96/stk/gsk"
 	"{{ .PkgName }}/mocks"
@@ -323,7 +336,7 @@ func Test{{ .ExportedName }}Handler(t *testing.T) {
 
 		{{ .ModName }}Handler := handler.New{{ .ExportedName }}Handler(service)
 
-		s.Get("/{{ .ModName }}", {{ .ModName }}Handler.{{ .ExportedName }}Handler)
+		s.Get("/{{ .ModName }}", gsk.E({{ .ModName }}Handler.{{ .ExportedName }}Handler))
 
 		// Act
 		w, _ := s.Test("GET", "/{{ .ModName }}", nil)
@@ -331,6 +344,25 @@ func Test{{ .ExportedName }}Handler(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
+
+	t.Run("{{ .ExportedName }} Handler returns 500 when {{ .ModName }} fails", func(t *testing.T) {
+
+		// Arrange
+		s := gsk.New()
+		service := mocks.New{{ .ExportedName }}Service(t)
+		service.On("{{ .ExportedName }}Service").Return("", serr.Err{{ .ExportedName }}Failed)
+
+		{{ .ModName }}Handler := handler.New{{ .ExportedName }}Handler(service)
+
+		s.Get("/{{ .ModName }}", gsk.E({{ .ModName }}Handler.{{ .ExportedName }}Handler))
+
+		// Act
+		w, _ := s.Test("GET", "/{{ .ModName }}", nil)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, "{\"error\": \"{{ .ModName }}_failed\", \"message\": \"{{ .ModName }} failed\"}", w.Body.String())
+	})
 }
 `,
 }
@@ -341,13 +373,18 @@ var INTERNALS_PING_API_HANDLER_PINGGO_MOD_TPL = Template{
 	Content: `package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"{{ .PkgName }}/internals/{{ .ModName }}/domain"
+	"{{ .PkgName }}/internals/{{ .ModName }}/serr"
 
 	"github.com/adharshmk96/stk/gsk"
 )
 
+// err{{ .ExportedName }}Failed is the response for serr.Err{{ .ExportedName }}Failed, the domain errors are mapped to responses in the handlers
+var err{{ .ExportedName }}Failed = gsk.NewHTTPError(http.StatusInternalServerError, "{{ .ModName }}_failed", "{{ .ModName }} failed")
+
 type {{ .ModName }}Handler struct {
 	service domain.{{ .ExportedName }}Service
 }
@@ -364,19 +401,20 @@ Response:
 - 200: OK
 - 500: Internal Server Error
 */
-func (h *{{ .ModName }}Handler) {{ .ExportedName }}Handler(gc *gsk.Context) {
+func (h *{{ .ModName }}Handler) {{ .ExportedName }}Handler(gc *gsk.Context) error {
 
 	message, err := h.service.{{ .ExportedName }}Service()
+	if errors.Is(err, serr.Err{{ .ExportedName }}Failed) {
+		return err{{ .ExportedName }}Failed.Wrap(err)
+	}
 	if err != nil {
-		gc.Status(http.StatusInternalServerError).JSONResponse(gsk.Map{
-			"error": err.Error(),
-		})
-		return
+		return err
 	}
 
 	gc.Status(http.StatusOK).JSONResponse(gsk.Map{
 		"message": message,
 	})
+	return nil
 }
 `,
 }
@@ -463,7 +501,7 @@ import "github.com/adharshmk96/stk/gsk"
 
 // Handler
 type {{ .ExportedName }}Handlers interface {
-	{{ .ExportedName }}Handler(gc *gsk.Context)
+	{{ .ExportedName }}Handler(gc *gsk.Context) error
 }
 `,
 }
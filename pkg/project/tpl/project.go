@@ -376,25 +376,40 @@ import (
 	"syscall"
 
 	"github.com/adharshmk96/stk/gsk"
+	"github.com/adharshmk96/stk/pkg/health"
 	"github.com/adharshmk96/stk/pkg/middleware"
+	"{{ .PkgName }}/public"
 	"{{ .PkgName }}/server/infra"
-	svrmw "{{ .PkgName }}/server/middleware"
+	"{{ .PkgName }}/server/infra/db"
 	"{{ .PkgName }}/server/routing"
 )
 
-func StartHttpServer(port string) (*gsk.Server, chan bool) {
+// NewHttpServer creates the server with the middlewares and routes
+func NewHttpServer(port string) *gsk.Server {
 
 	logger := infra.GetLogger()
 
+	templateEngine, err := gsk.NewTemplateEngine(gsk.TemplateEngineConfig{
+		FS:  public.Files,
+		Dir: "templates",
+	})
+	if err != nil {
+		logger.Error("error loading templates", "error", err)
+		panic(err)
+	}
+
 	serverConfig := &gsk.ServerConfig{
-		Port:   port,
-		Logger: logger,
+		Port:           port,
+		Logger:         logger,
+		StaticFS:       public.AssetsFS(),
+		TemplateEngine: templateEngine,
 	}
 
 	server := gsk.New(serverConfig)
 
-	rateLimiter := svrmw.RateLimiter()
-	server.Use(rateLimiter)
+	server.Use(middleware.RequestID())
+	server.Use(middleware.TraceContext)
+	server.Use(middleware.Metrics())
 	server.Use(middleware.RequestLogger)
 	server.Use(middleware.CORS(middleware.CORSConfig{
 		AllowAll: true,
@@ -404,6 +419,20 @@ func StartHttpServer(port string) (*gsk.Server, chan bool) {
 
 	routing.SetupTemplateRoutes(server)
 	routing.SetupApiRoutes(server)
+	server.MetricsHandler("/metrics")
+	server.HealthChecks(gsk.HealthCheck{Name: "db", Check: health.SQL(db.GetSqliteConnection())})
+	// set SwaggerUIPath to serve a Swagger UI page, eg: /docs, it loads the swagger-ui scripts from a CDN by default
+	server.OpenAPI(gsk.OpenAPIConfig{
+		Title: "{{ .AppName }}",
+	})
+
+	return server
+}
+
+func StartHttpServer(port string) (*gsk.Server, chan bool) {
+
+	logger := infra.GetLogger()
+	server := NewHttpServer(port)
 
 	server.Start()
 
@@ -552,6 +581,7 @@ var SERVER_ROUTING_SETUPROUTESGO_TPL = Template{
 
 import (
 	"github.com/adharshmk96/stk/gsk"
+	svrmw "{{ .PkgName }}/server/middleware"
 )
 
 var webRouteGroups = []func(*gsk.RouteGroup){}
@@ -567,6 +597,8 @@ func RegisterWebRoutes(routeGroup func(*gsk.RouteGroup)) {
 
 func SetupApiRoutes(server *gsk.Server) {
 	apiRoutes := server.RouteGroup("/api")
+	// only the api is rate limited, health checks and metrics are scraped often
+	apiRoutes.Use(svrmw.RateLimiter())
 
 	for _, routeGroup := range apiRouteGroups {
 		routeGroup(apiRoutes)
@@ -592,13 +624,13 @@ var PUBLIC_TEMPLATES_INDEXHTML_TPL = Template{
     <head>
     <meta charset="UTF-8">
     <title>{{ .Var.Title }}</title>
-    <link rel="stylesheet" href="{{ .Config.Static }}/style.css">
+    <link rel="stylesheet" href="{{ asset "styles.css" }}">
 </head>
 <body>
     <h1>{{ .Var.Title }}</h1>
     <p>{{ .Var.Content }}</p>
 
-    <script src="{{ .Config.Static }}/script.js"></script>
+    <script src="{{ asset "script.js" }}"></script>
 </body>
 </html>
 `,
@@ -701,6 +733,70 @@ var VSCODE_LAUNCHJSON_TPL = Template{
 }`,
 }
 
+var PUBLIC_PUBLICGO_TPL = Template{
+	FilePath: "public/public.go",
+	Render: true,
+	Content: `package public
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// Files embeds the assets and templates into the binary
+// so that the binary can be shipped without the public directory
+//
+//go:embed assets templates
+var Files embed.FS
+
+// AssetsFS returns the static assets with the assets directory as the root
+func AssetsFS() fs.FS {
+	assets, err := fs.Sub(Files, "assets")
+	if err != nil {
+		panic(err)
+	}
+	return assets
+}
+`,
+}
+
+var CMD_ROUTESGO_TPL = Template{
+	FilePath: "cmd/routes.go",
+	Render: true,
+	Content: `package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"{{ .PkgName }}/server"
+	"github.com/spf13/cobra"
+)
+
+// routesCmd represents the routes command
+var routesCmd = &cobra.Command{
+	Use:   "routes",
+	Short: "List the registered routes",
+	Run: func(cmd *cobra.Command, args []string) {
+		httpServer := server.NewHttpServer("0.0.0.0:" + startingPort)
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "METHOD\tPATH\tHANDLER\tMIDDLEWARES")
+		for _, route := range httpServer.Routes() {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", route.Method, route.Path, route.Handler, strings.Join(route.Middlewares, ", "))
+		}
+		writer.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(routesCmd)
+}
+`,
+}
+
 var ProjectTemplates = []Template{
 	DOCKERCOMPOSEYAML_TPL,
 	GORELEASERYAML_TPL,
@@ -725,4 +821,6 @@ var ProjectTemplates = []Template{
 	GITHUB_WORKFLOWS_GOBUILDTESTYML_TPL,
 	GITHUB_WORKFLOWS_GORELEASEYML_TPL,
 	VSCODE_LAUNCHJSON_TPL,
+	PUBLIC_PUBLICGO_TPL,
+	CMD_ROUTESGO_TPL,
 }
@@ -1,17 +1,23 @@
 package sqlmigrator
 
 import (
+	"context"
 	"fmt"
 	"slices"
+
+	"github.com/adharshmk96/stk/pkg/tracing"
 )
 
 type migrator struct {
 	DBRepo DBRepo
+	// Tracer starts a span for the migration run and for every migration applied
+	Tracer tracing.Tracer
 }
 
 func NewMigrator(dbRepo DBRepo) *migrator {
 	return &migrator{
 		DBRepo: dbRepo,
+		Tracer: tracing.NoopTracer{},
 	}
 }
 
@@ -29,6 +35,9 @@ func (m *migrator) MigrateUp(ctx *Context, num int) ([]*MigrationFileEntry, erro
 		migrationToApply = migrationToApply[:num]
 	}
 
+	runCtx, runSpan := m.startRunSpan(ctx, MigrationUp, len(migrationToApply))
+	defer runSpan.End()
+
 	for _, migration := range migrationToApply {
 		if ctx.DryRun {
 			displayMigration(migration)
@@ -37,25 +46,14 @@ func (m *migrator) MigrateUp(ctx *Context, num int) ([]*MigrationFileEntry, erro
 
 		upFileContent, _ := migration.LoadFileContent()
 
-		err := m.DBRepo.Exec(upFileContent)
+		err := m.apply(runCtx, ctx, migration, MigrationUp, upFileContent)
 		if err != nil {
+			runSpan.RecordError(err)
 			return appliedMigrations, err
 		}
 
 		migration.Committed = true
 		appliedMigrations = append(appliedMigrations, migration)
-
-		// commit to db migration table
-		dbEntry := &MigrationDBEntry{
-			Number:    migration.Number,
-			Name:      migration.Name,
-			Direction: "up",
-		}
-
-		err = m.DBRepo.PushHistory(dbEntry)
-		if err != nil {
-			return appliedMigrations, err
-		}
 	}
 
 	return appliedMigrations, nil
@@ -77,6 +75,9 @@ func (m *migrator) MigrateDown(ctx *Context, num int) ([]*MigrationFileEntry, er
 		migrationToApply = migrationToApply[:num]
 	}
 
+	runCtx, runSpan := m.startRunSpan(ctx, MigrationDown, len(migrationToApply))
+	defer runSpan.End()
+
 	for _, migration := range migrationToApply {
 		if ctx.DryRun {
 			displayMigration(migration)
@@ -85,28 +86,59 @@ func (m *migrator) MigrateDown(ctx *Context, num int) ([]*MigrationFileEntry, er
 
 		_, downFileContent := migration.LoadFileContent()
 
-		err := m.DBRepo.Exec(downFileContent)
+		err := m.apply(runCtx, ctx, migration, MigrationDown, downFileContent)
 		if err != nil {
+			runSpan.RecordError(err)
 			return rolledBackMigrations, err
 		}
 
 		migration.Committed = false
 		rolledBackMigrations = append(rolledBackMigrations, migration)
+	}
 
-		// commit to db migration table
-		dbEntry := &MigrationDBEntry{
-			Number:    migration.Number,
-			Name:      migration.Name,
-			Direction: "down",
-		}
+	return rolledBackMigrations, nil
+}
 
-		err = m.DBRepo.PushHistory(dbEntry)
-		if err != nil {
-			return rolledBackMigrations, err
-		}
+// apply executes the migration query and commits it to the db migration table
+// within a span of the migration
+func (m *migrator) apply(spanCtx context.Context, ctx *Context, migration *MigrationFileEntry, direction MigrationType, query string) error {
+	_, span := m.Tracer.Start(spanCtx, "migration "+string(direction)+" "+migration.String(),
+		tracing.Int("migration.number", migration.Number),
+		tracing.String("migration.name", migration.Name),
+		tracing.String("migration.direction", string(direction)),
+		tracing.String("db.system", string(ctx.Database)),
+	)
+	defer span.End()
+
+	err := m.DBRepo.Exec(query)
+	if err != nil {
+		span.RecordError(err)
+		return err
 	}
 
-	return rolledBackMigrations, nil
+	// commit to db migration table
+	dbEntry := &MigrationDBEntry{
+		Number:    migration.Number,
+		Name:      migration.Name,
+		Direction: string(direction),
+	}
+
+	err = m.DBRepo.PushHistory(dbEntry)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (m *migrator) startRunSpan(ctx *Context, direction MigrationType, count int) (context.Context, tracing.Span) {
+	return m.Tracer.Start(context.Background(), "migrate "+string(direction),
+		tracing.String("migration.direction", string(direction)),
+		tracing.String("db.system", string(ctx.Database)),
+		tracing.Int("migration.count", count),
+		tracing.Bool("migration.dry_run", ctx.DryRun),
+	)
 }
 
 func (m *migrator) MigrationHistory(ctx *Context) ([]*MigrationDBEntry, error) {
@@ -1,12 +1,14 @@
 package sqlmigrator_test
 
 import (
+	"errors"
 	"os"
 	"path"
 	"testing"
 
 	"github.com/adharshmk96/stk/mocks"
 	sqlmigrator "github.com/adharshmk96/stk/pkg/sqlMigrator"
+	"github.com/adharshmk96/stk/pkg/tracing"
 	"github.com/adharshmk96/stk/testutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -339,3 +341,59 @@ func TestMigrateDown(t *testing.T) {
 		checkUnappliedMigrations(t, ctx, 0)
 	})
 }
+
+func TestMigratorTracing(t *testing.T) {
+
+	var LOG_FILE_CONTENT = `1_create_users_table_up
+2_create_posts_table_down
+3_create_comments_table_down
+`
+
+	t.Run("migrate up starts a span for every migration", func(t *testing.T) {
+		tempDir, removeDir := testutils.CreateTempDirectory(t)
+
+		defer removeDir()
+
+		ctx := sqlmigrator.NewContext(tempDir, sqlmigrator.SQLiteDB, "migrator.log", false)
+
+		logFilePath := path.Join(ctx.WorkDir, ctx.LogFile)
+		err := os.WriteFile(logFilePath, []byte(LOG_FILE_CONTENT), 0644)
+		assert.NoError(t, err)
+		err = ctx.LoadMigrationEntries()
+		assert.NoError(t, err)
+
+		execErr := errors.New("syntax error")
+		dbMock := mocks.NewDBRepo(t)
+		dbMock.On("Exec", mock.AnythingOfType("string")).Return(nil).Once()
+		dbMock.On("Exec", mock.AnythingOfType("string")).Return(execErr).Once()
+		dbMock.On("PushHistory", mock.Anything).Return(nil)
+
+		tracer := tracing.NewInMemoryTracer()
+		migrator := sqlmigrator.NewMigrator(dbMock)
+		migrator.Tracer = tracer
+
+		_, err = migrator.MigrateUp(ctx, 0)
+		assert.ErrorIs(t, err, execErr)
+
+		spans := tracer.Spans()
+		assert.Equal(t, 3, len(spans))
+
+		assert.Equal(t, "migration up 2_create_posts_table", spans[0].Name)
+		assert.Equal(t, 2, spans[0].Attributes["migration.number"])
+		assert.Equal(t, "create_posts_table", spans[0].Attributes["migration.name"])
+		assert.Equal(t, "sqlite", spans[0].Attributes["db.system"])
+		assert.NoError(t, spans[0].Err)
+
+		assert.Equal(t, "migration up 3_create_comments_table", spans[1].Name)
+		assert.ErrorIs(t, spans[1].Err, execErr)
+
+		run := spans[2]
+		assert.Equal(t, "migrate up", run.Name)
+		assert.Equal(t, 2, run.Attributes["migration.count"])
+		assert.ErrorIs(t, run.Err, execErr)
+		for _, span := range spans[:2] {
+			assert.Equal(t, run.TraceID, span.TraceID)
+			assert.Equal(t, run.SpanID, span.ParentSpanID)
+		}
+	})
+}
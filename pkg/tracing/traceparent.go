@@ -0,0 +1,183 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// W3C trace context headers, https://www.w3.org/TR/trace-context/
+const (
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+
+	// FlagSampled is set in the trace flags when the caller may have recorded the trace
+	FlagSampled byte = 0x01
+
+	maxTraceStateMembers = 32
+)
+
+var ErrInvalidTraceParent = errors.New("invalid traceparent")
+
+var (
+	zeroTraceID = strings.Repeat("0", 32)
+	zeroSpanID  = strings.Repeat("0", 16)
+)
+
+// TraceContext identifies the position of a request in a distributed trace
+// TraceID is 32 and SpanID is 16 lowercase hex characters
+type TraceContext struct {
+	TraceID string
+	SpanID  string
+	Flags   byte
+	// State is the vendor specific tracestate, propagated as it is
+	State string
+}
+
+// ParseTraceParent parses the traceparent header value
+// format: version-traceid-parentid-flags, eg: 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func ParseTraceParent(value string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, ErrInvalidTraceParent
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	// version 00 has exactly 4 parts, future versions may append more
+	if !isHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return TraceContext{}, ErrInvalidTraceParent
+	}
+	if !isHex(traceID, 32) || traceID == zeroTraceID {
+		return TraceContext{}, ErrInvalidTraceParent
+	}
+	if !isHex(spanID, 16) || spanID == zeroSpanID {
+		return TraceContext{}, ErrInvalidTraceParent
+	}
+	if !isHex(flags, 2) {
+		return TraceContext{}, ErrInvalidTraceParent
+	}
+
+	flagBytes, _ := hex.DecodeString(flags)
+	return TraceContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Flags:   flagBytes[0],
+	}, nil
+}
+
+// ParseTraceState validates the tracestate header value and returns it normalized
+// invalid values are dropped as recommended by the specification
+func ParseTraceState(value string) string {
+	var members []string
+	for _, member := range strings.Split(value, ",") {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(member, "=")
+		if !ok || key == "" || val == "" || strings.ContainsAny(key, " \t") {
+			return ""
+		}
+		members = append(members, member)
+	}
+	if len(members) > maxTraceStateMembers {
+		return ""
+	}
+	return strings.Join(members, ",")
+}
+
+// New starts a new sampled trace
+func New() TraceContext {
+	return TraceContext{
+		TraceID: randomHex(16),
+		SpanID:  randomHex(8),
+		Flags:   FlagSampled,
+	}
+}
+
+// Extract reads the trace context from the traceparent and tracestate headers
+func Extract(header http.Header) (TraceContext, error) {
+	tc, err := ParseTraceParent(header.Get(HeaderTraceParent))
+	if err != nil {
+		return TraceContext{}, err
+	}
+	tc.State = ParseTraceState(strings.Join(header.Values(HeaderTraceState), ","))
+	return tc, nil
+}
+
+// Inject writes the trace context into the traceparent and tracestate headers
+// use it to propagate the trace to outgoing requests
+func (tc TraceContext) Inject(header http.Header) {
+	if !tc.IsValid() {
+		return
+	}
+	header.Set(HeaderTraceParent, tc.TraceParent())
+	if tc.State != "" {
+		header.Set(HeaderTraceState, tc.State)
+	} else {
+		header.Del(HeaderTraceState)
+	}
+}
+
+// Child returns the context of a new span in the same trace
+func (tc TraceContext) Child() TraceContext {
+	tc.SpanID = randomHex(8)
+	return tc
+}
+
+// TraceParent returns the traceparent header value
+func (tc TraceContext) TraceParent() string {
+	return "00-" + tc.TraceID + "-" + tc.SpanID + "-" + hex.EncodeToString([]byte{tc.Flags})
+}
+
+func (tc TraceContext) IsSampled() bool {
+	return tc.Flags&FlagSampled == FlagSampled
+}
+
+func (tc TraceContext) IsValid() bool {
+	return isHex(tc.TraceID, 32) && tc.TraceID != zeroTraceID && isHex(tc.SpanID, 16) && tc.SpanID != zeroSpanID
+}
+
+type traceContextKey struct{}
+
+// ContextWithTrace returns a copy of the context carrying the trace context
+func ContextWithTrace(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// FromContext returns the trace context carried by the context
+func FromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextKey{}).(TraceContext)
+	return tc, ok
+}
+
+// isHex reports whether the value is a lowercase hex string of the given length
+func isHex(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, r := range value {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func randomHex(size int) string {
+	b := make([]byte, size)
+	for {
+		if _, err := rand.Read(b); err != nil {
+			panic(err)
+		}
+		// all zero ids are invalid
+		for _, v := range b {
+			if v != 0 {
+				return hex.EncodeToString(b)
+			}
+		}
+	}
+}
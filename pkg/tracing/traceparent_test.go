@@ -0,0 +1,98 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/adharshmk96/stk/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	spanID      = "00f067aa0ba902b7"
+	traceParent = "00-" + traceID + "-" + spanID + "-01"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	t.Run("parses valid traceparent", func(t *testing.T) {
+		tc, err := tracing.ParseTraceParent(traceParent)
+
+		assert.NoError(t, err)
+		assert.Equal(t, traceID, tc.TraceID)
+		assert.Equal(t, spanID, tc.SpanID)
+		assert.True(t, tc.IsSampled())
+		assert.Equal(t, traceParent, tc.TraceParent())
+	})
+
+	t.Run("accepts future versions with extra fields", func(t *testing.T) {
+		tc, err := tracing.ParseTraceParent("01-" + traceID + "-" + spanID + "-00-extra")
+
+		assert.NoError(t, err)
+		assert.False(t, tc.IsSampled())
+	})
+
+	invalid := []string{
+		"",
+		"00-" + traceID + "-" + spanID,
+		"00-" + traceID + "-" + spanID + "-01-extra",
+		"ff-" + traceID + "-" + spanID + "-01",
+		"00-00000000000000000000000000000000-" + spanID + "-01",
+		"00-" + traceID + "-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-" + spanID + "-01",
+		"00-" + traceID + "-" + spanID + "-1",
+		"00-" + traceID[1:] + "-" + spanID + "-01",
+	}
+	for _, value := range invalid {
+		_, err := tracing.ParseTraceParent(value)
+		assert.ErrorIs(t, err, tracing.ErrInvalidTraceParent, value)
+	}
+}
+
+func TestParseTraceState(t *testing.T) {
+	assert.Equal(t, "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7", tracing.ParseTraceState("congo=t61rcWkgMzE, rojo=00f067aa0ba902b7"))
+	assert.Equal(t, "", tracing.ParseTraceState("congo"))
+	assert.Equal(t, "", tracing.ParseTraceState(""))
+}
+
+func TestTraceContext(t *testing.T) {
+	t.Run("extracts and injects headers", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Traceparent", traceParent)
+		header.Add("Tracestate", "congo=t61rcWkgMzE")
+		header.Add("Tracestate", "rojo=00f067aa0ba902b7")
+
+		tc, err := tracing.Extract(header)
+		assert.NoError(t, err)
+		assert.Equal(t, "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7", tc.State)
+
+		child := tc.Child()
+		assert.Equal(t, tc.TraceID, child.TraceID)
+		assert.NotEqual(t, tc.SpanID, child.SpanID)
+		assert.True(t, child.IsValid())
+
+		outgoing := http.Header{}
+		child.Inject(outgoing)
+		assert.Equal(t, "00-"+traceID+"-"+child.SpanID+"-01", outgoing.Get("traceparent"))
+		assert.Equal(t, tc.State, outgoing.Get("tracestate"))
+	})
+
+	t.Run("starts a new sampled trace", func(t *testing.T) {
+		tc := tracing.New()
+
+		assert.True(t, tc.IsValid())
+		assert.True(t, tc.IsSampled())
+		assert.NotEqual(t, tc.TraceID, tracing.New().TraceID)
+	})
+
+	t.Run("stores trace context in context", func(t *testing.T) {
+		_, ok := tracing.FromContext(context.Background())
+		assert.False(t, ok)
+
+		tc := tracing.New()
+		stored, ok := tracing.FromContext(tracing.ContextWithTrace(context.Background(), tc))
+		assert.True(t, ok)
+		assert.Equal(t, tc, stored)
+	})
+}
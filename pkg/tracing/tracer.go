@@ -0,0 +1,157 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Tracer starts spans, it is a small subset of the OpenTelemetry tracer
+// so that an adapter over the OpenTelemetry SDK can implement it
+type Tracer interface {
+	// Start starts a span as a child of the span in the context, if any
+	// the returned context carries the new span
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a unit of work in a trace, End must be called once the work is done
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// RecordError records the error and marks the span as failed
+	RecordError(err error)
+	End()
+}
+
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key string, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// NoopTracer starts spans which do nothing
+type NoopTracer struct{}
+
+func (NoopTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
+
+// SpanData is a finished span recorded by the InMemoryTracer
+type SpanData struct {
+	Name         string
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Attributes   map[string]interface{}
+	Err          error
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
+func (sd SpanData) Duration() time.Duration {
+	return sd.EndTime.Sub(sd.StartTime)
+}
+
+// InMemoryTracer records the finished spans in memory, used in tests
+// spans continue the trace of the TraceContext in the context, see ContextWithTrace
+type InMemoryTracer struct {
+	mux   sync.Mutex
+	spans []SpanData
+}
+
+func NewInMemoryTracer() *InMemoryTracer {
+	return &InMemoryTracer{}
+}
+
+func (t *InMemoryTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	parent, ok := FromContext(ctx)
+
+	tc := New()
+	parentSpanID := ""
+	if ok {
+		tc = parent.Child()
+		parentSpanID = parent.SpanID
+	}
+
+	span := &inMemorySpan{
+		tracer: t,
+		data: SpanData{
+			Name:         name,
+			TraceID:      tc.TraceID,
+			SpanID:       tc.SpanID,
+			ParentSpanID: parentSpanID,
+			Attributes:   map[string]interface{}{},
+			StartTime:    time.Now(),
+		},
+	}
+	span.SetAttributes(attrs...)
+
+	return ContextWithTrace(ctx, tc), span
+}
+
+// Spans returns the finished spans in the order they ended
+func (t *InMemoryTracer) Spans() []SpanData {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return append([]SpanData(nil), t.spans...)
+}
+
+// Reset removes the recorded spans
+func (t *InMemoryTracer) Reset() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.spans = nil
+}
+
+type inMemorySpan struct {
+	tracer *InMemoryTracer
+	mux    sync.Mutex
+	data   SpanData
+	ended  bool
+}
+
+func (s *inMemorySpan) SetAttributes(attrs ...Attribute) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, attr := range attrs {
+		s.data.Attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *inMemorySpan) RecordError(err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.data.Err = err
+}
+
+func (s *inMemorySpan) End() {
+	s.mux.Lock()
+	if s.ended {
+		s.mux.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	s.mux.Unlock()
+
+	s.tracer.mux.Lock()
+	defer s.tracer.mux.Unlock()
+	s.tracer.spans = append(s.tracer.spans, data)
+}
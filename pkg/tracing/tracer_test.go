@@ -0,0 +1,67 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/adharshmk96/stk/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryTracer(t *testing.T) {
+	t.Run("records finished spans with parents", func(t *testing.T) {
+		tracer := tracing.NewInMemoryTracer()
+
+		ctx, parent := tracer.Start(context.Background(), "parent", tracing.String("key", "value"))
+		_, child := tracer.Start(ctx, "child")
+		child.SetAttributes(tracing.Int("count", 2), tracing.Bool("ok", false))
+		child.RecordError(errors.New("failed"))
+		child.End()
+		child.End()
+
+		assert.Equal(t, 1, len(tracer.Spans()))
+		parent.End()
+
+		spans := tracer.Spans()
+		assert.Equal(t, 2, len(spans))
+
+		childData, parentData := spans[0], spans[1]
+		assert.Equal(t, "child", childData.Name)
+		assert.Equal(t, parentData.TraceID, childData.TraceID)
+		assert.Equal(t, parentData.SpanID, childData.ParentSpanID)
+		assert.Equal(t, map[string]interface{}{"count": 2, "ok": false}, childData.Attributes)
+		assert.EqualError(t, childData.Err, "failed")
+		assert.True(t, childData.Duration() >= 0)
+
+		assert.Equal(t, "", parentData.ParentSpanID)
+		assert.Equal(t, "value", parentData.Attributes["key"])
+
+		tracer.Reset()
+		assert.Empty(t, tracer.Spans())
+	})
+
+	t.Run("continues the trace in the context", func(t *testing.T) {
+		tracer := tracing.NewInMemoryTracer()
+		remote := tracing.New()
+
+		ctx, span := tracer.Start(tracing.ContextWithTrace(context.Background(), remote), "request")
+		span.End()
+
+		tc, _ := tracing.FromContext(ctx)
+		data := tracer.Spans()[0]
+		assert.Equal(t, remote.TraceID, data.TraceID)
+		assert.Equal(t, remote.SpanID, data.ParentSpanID)
+		assert.Equal(t, tc.SpanID, data.SpanID)
+	})
+
+	t.Run("noop tracer returns the context", func(t *testing.T) {
+		ctx := context.Background()
+		spanCtx, span := tracing.NoopTracer{}.Start(ctx, "noop")
+		span.SetAttributes(tracing.String("key", "value"))
+		span.RecordError(errors.New("failed"))
+		span.End()
+
+		assert.Equal(t, ctx, spanCtx)
+	})
+}